@@ -0,0 +1,102 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"github.com/voronelf/logview/core"
+	"strings"
+	"time"
+)
+
+// conditionHelp describes syntax of condition in option -c
+const conditionHelp = `    -c condition   Filter condition. Contains one or more field checks.
+                   Every field check is 'fieldName : fieldValue', where
+                     fieldName  - name of field; can be wildcard with '*'
+                     fieldValue - value of field, case insensitive;
+                                  can be many values divided '|';
+                                  every value can be wildcard with '*';
+                                  every value can be negative, starts from '!'
+                   Field check can be a comparison 'fieldName op fieldValue', where op is
+                   one of '=', '!=', '>', '>=', '<', '<='. Values are compared as numbers
+                   if both of them are numbers, else lexically. Times are compared, if value
+                   is time like '2024-05-01T10:00' or relative time like 'now-15m', 'now+1h',
+                   'now-2d'. Time in field can be in RFC3339 or other common layout, or unix
+                   timestamp in seconds or milliseconds. 'fieldName between from and to'
+                   checks range of numbers or times. Field '_time' is alias of time field.
+                   'fieldName in (a, b, c)' checks that value is equal to any of values,
+                   'fieldName in @name' takes values from list file, one value per line.
+                   Level fields are compared by severity, if value is name of level, e.g.
+                   'level >= warn'. Names like 'WARNING', 'crit', numeric levels of bunyan
+                   and pino (30, 40, 50) and syslog severities are normalized to levels
+                   trace < debug < info < warn < error < fatal. Field '_level' is alias
+                   of any level field.
+                   Operations '~' and '!~' check field value by regular expression,
+                   e.g. 'message ~ "timeout|refused"'. Expression is case insensitive,
+                   it can be changed by flag in the beginning: "(?-i)Expression".
+                   Operation can be followed by modifier '/c' for case sensitive matching
+                   or '/i' for case insensitive matching, e.g. 'userId =/c "aGVsbG8="'.
+                   Field of nested object or array is selected by path like 'user.name',
+                   'errors[0].code' or 'errors[*].code'. Arrays match if any element matches,
+                   'all(fieldName): fieldValue' requires every element to match.
+                   Functions check presence and type of field: exists(fieldName),
+                   missing(fieldName), is_null(fieldName), is_number(fieldName),
+                   is_string(fieldName), is_bool(fieldName), is_array(fieldName),
+                   is_object(fieldName). Null value can be matched as 'null'.
+                   in_cidr(fieldName, "10.0.0.0/8", "fd00::/8", ...) checks that IP address
+                   is in any of networks, networks must be quoted. Argument '@name' is list
+                   of networks from file, one network per line. Name of list is name from
+                   section [lists] of settings file ~/.logview/settings.toml, like
+                   'office = "/path/to/office.txt"', or path of file.
+                   Macro '@name' is condition from section [macros] of settings file, like
+                   no_healthchecks = "not url: '/health*'", it is used as condition in
+                   brackets, e.g. '@no_healthchecks and level: error'. Macros can refer to
+                   other macros.
+                   Word or quoted string without field operation searches text in values
+                   of all fields, case insensitive, e.g. 'level:error and "connection reset"'.
+                   Field checks are divided by logic operations: 'and', 'or', and can be
+                   negated by 'not'. 'not' binds tightest, then 'and', then 'or'.
+                   Also you can use brackets for prioritize operations.
+`
+
+// filterParamsHelp describes options, which change matching of condition
+const filterParamsHelp = `    -sn            Search text without field also in names of fields.
+    -cs            Case sensitive matching of names and values of fields.
+    -tz timezone   Timezone for times without zone, like 'UTC' or 'Europe/Moscow'.
+                   Local timezone by default.
+    -lf fields     Comma-separated list of fields with severity level in order of priority.
+                   By default 'level,lvl,severity,log.level,loglevel,levelname'.
+`
+
+// splitFields splits comma-separated list of fields, empty names are skipped
+func splitFields(list string) []string {
+	var fields []string
+	for _, field := range strings.Split(list, ",") {
+		field = strings.TrimSpace(field)
+		if field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// parseTimezone parses timezone for times without zone, empty timezone means local
+func parseTimezone(timezone string) (*time.Location, error) {
+	if timezone == "" {
+		return time.Local, nil
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, errors.New("unknown timezone: " + timezone)
+	}
+	return location, nil
+}
+
+// formatFilterError shows condition with caret under wrong token for errors in condition
+func formatFilterError(err error) string {
+	condErr, ok := err.(*core.ConditionError)
+	if !ok {
+		return err.Error()
+	}
+	condition := strings.NewReplacer("\n", " ", "\r", " ", "\t", " ").Replace(condErr.Condition)
+	return fmt.Sprintf("Invalid condition: %s\n    %s\n    %s^", condErr.Error(), condition, strings.Repeat(" ", condErr.Column-1))
+}
@@ -0,0 +1,56 @@
+package command
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/voronelf/logview/core"
+	"testing"
+	"time"
+)
+
+func TestFormatFilterError(t *testing.T) {
+	err := &core.ConditionError{
+		Condition: "level: error and\tmsg == x",
+		Column:    23,
+		Message:   "Unexpected token '='",
+		Expected:  "field value",
+	}
+	expected := "Invalid condition: Unexpected token '=' at column 23, expected field value\n" +
+		"    level: error and msg == x\n" +
+		"                          ^"
+	assert.Equal(t, expected, formatFilterError(err))
+}
+
+func TestFormatFilterError_Macro(t *testing.T) {
+	err := &core.ConditionError{
+		Condition: "not url ==",
+		Column:    11,
+		Message:   "Unexpected end of condition",
+		Expected:  "field value",
+		Macro:     "no_healthchecks",
+	}
+	expected := "Invalid condition: Unexpected end of condition at column 11 of macro 'no_healthchecks', expected field value\n" +
+		"    not url ==\n" +
+		"              ^"
+	assert.Equal(t, expected, formatFilterError(err))
+}
+
+func TestParseTimezone(t *testing.T) {
+	location, err := parseTimezone("")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Local, location)
+	location, err = parseTimezone("UTC")
+	assert.Nil(t, err)
+	assert.Equal(t, time.UTC, location)
+	_, err = parseTimezone("Wrong/Zone")
+	assert.NotNil(t, err)
+}
+
+func TestFormatFilterError_OtherError(t *testing.T) {
+	assert.Equal(t, "some error", formatFilterError(errors.New("some error")))
+}
+
+func TestSplitFields(t *testing.T) {
+	assert.Equal(t, []string{"level", "lvl"}, splitFields(" level, ,lvl "))
+	assert.Nil(t, splitFields(""))
+}
@@ -0,0 +1,137 @@
+package command
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/mitchellh/cli"
+	"github.com/voronelf/logview/core"
+	"sort"
+	"strings"
+	"time"
+)
+
+// dedupSummary is printed, when burst of duplicated rows is ended
+const dedupSummary = "Last message repeated %d times: %s"
+
+// dedupHelp describes options -dedup, -dk and -dw of duplicates suppression
+const dedupHelp = `    -dedup         Suppress duplicates of matched rows. First row is shown, next rows with same
+                   key fields are hidden until they stop for window, then count of them is shown.
+    -dk fields     Comma-separated list of key fields of duplicates, 'message,level' by default.
+    -dw window     Time window of duplicates, '10s' by default.
+`
+
+// dedupParams are options of duplicates suppression
+type dedupParams struct {
+	enabled    bool
+	fieldsList string
+	fields     []string
+	window     time.Duration
+}
+
+func (p *dedupParams) addFlags(cmdFlags *flag.FlagSet) {
+	cmdFlags.BoolVar(&p.enabled, "dedup", false, "")
+	cmdFlags.StringVar(&p.fieldsList, "dk", "message,level", "")
+	cmdFlags.DurationVar(&p.window, "dw", 10*time.Second, "")
+}
+
+// resolve parses list of key fields
+func (p dedupParams) resolve() (dedupParams, error) {
+	p.fields = splitFields(p.fieldsList)
+	if !p.enabled {
+		return p, nil
+	}
+	if len(p.fields) == 0 {
+		return p, errors.New("key fields of duplicates are empty")
+	}
+	if p.window <= 0 {
+		return p, errors.New("window of duplicates must be positive")
+	}
+	return p, nil
+}
+
+// newDedupStage creates stage of suppression of duplicates, params must be resolved
+func newDedupStage(ui cli.Ui, params dedupParams) *dedupStage {
+	return &dedupStage{
+		ui:     ui,
+		fields: params.fields,
+		window: params.window,
+		now:    time.Now,
+		bursts: map[string]*dedupBurst{},
+	}
+}
+
+// dedupStage doesn't pass rows, which repeat key fields of earlier passed row within window
+type dedupStage struct {
+	ui     cli.Ui
+	fields []string
+	window time.Duration
+	now    func() time.Time
+	// bursts are last matched rows by their keys
+	bursts map[string]*dedupBurst
+}
+
+type dedupBurst struct {
+	lastSeen time.Time
+	repeats  int
+}
+
+var _ rowStage = (*dedupStage)(nil)
+
+func (f *dedupStage) pass(row core.Row) bool {
+	key := f.rowKey(row)
+	if key == "" {
+		return true
+	}
+	now := f.now()
+	burst, ok := f.bursts[key]
+	if ok && now.Sub(burst.lastSeen) <= f.window {
+		burst.repeats++
+		burst.lastSeen = now
+		return false
+	}
+	if ok {
+		f.summary(key, burst)
+	}
+	f.bursts[key] = &dedupBurst{lastSeen: now}
+	return true
+}
+
+// rowKey returns key like 'message=timeout, level=error' or empty string, if row has no key fields
+func (f *dedupStage) rowKey(row core.Row) string {
+	parts := make([]string, 0, len(f.fields))
+	found := false
+	for _, field := range f.fields {
+		value, ok := row.Data[field]
+		if ok {
+			found = true
+		}
+		parts = append(parts, field+"="+fmt.Sprint(value))
+	}
+	if !found {
+		return ""
+	}
+	return strings.Join(parts, ", ")
+}
+
+// flush prints summaries of ended bursts
+func (f *dedupStage) flush(final bool) {
+	now := f.now()
+	keys := make([]string, 0, len(f.bursts))
+	for key, burst := range f.bursts {
+		if final || now.Sub(burst.lastSeen) > f.window {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		f.summary(key, f.bursts[key])
+		delete(f.bursts, key)
+	}
+}
+
+func (f *dedupStage) summary(key string, burst *dedupBurst) {
+	if burst.repeats > 0 {
+		f.ui.Output(fmt.Sprintf(dedupSummary, burst.repeats, key))
+	}
+}
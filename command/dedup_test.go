@@ -0,0 +1,86 @@
+package command
+
+import (
+	"bytes"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/assert"
+	"github.com/voronelf/logview/core"
+	"testing"
+	"time"
+)
+
+func newDedupStageForTest(now *time.Time) (*dedupStage, *cli.MockUi) {
+	ui := &cli.MockUi{OutputWriter: &bytes.Buffer{}, ErrorWriter: &bytes.Buffer{}}
+	params, _ := dedupParams{enabled: true, fieldsList: "message,level", window: 10 * time.Second}.resolve()
+	stage := newDedupStage(ui, params)
+	stage.now = func() time.Time {
+		return *now
+	}
+	return stage, ui
+}
+
+func newDedupRow(message, level string) core.Row {
+	return core.Row{Data: map[string]interface{}{"message": message, "level": level}}
+}
+
+func TestDedupStage_Pass(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	stage, ui := newDedupStageForTest(&now)
+
+	assert.True(t, stage.pass(newDedupRow("timeout", "error")))
+	assert.False(t, stage.pass(newDedupRow("timeout", "error")))
+	assert.True(t, stage.pass(newDedupRow("timeout", "warn")))
+	assert.True(t, stage.pass(newDedupRow("refused", "error")))
+	now = now.Add(5 * time.Second)
+	assert.False(t, stage.pass(newDedupRow("timeout", "error")))
+	assert.True(t, stage.pass(core.Row{Data: map[string]interface{}{"other": "value"}}))
+	assert.True(t, stage.pass(core.Row{Data: map[string]interface{}{"other": "value"}}))
+	assert.Equal(t, "", ui.OutputWriter.String())
+
+	// burst is continued by every duplicate
+	now = now.Add(9 * time.Second)
+	stage.flush(false)
+	assert.Equal(t, "", ui.OutputWriter.String())
+
+	now = now.Add(time.Second)
+	assert.False(t, stage.pass(newDedupRow("timeout", "error")))
+	now = now.Add(11 * time.Second)
+	assert.True(t, stage.pass(newDedupRow("timeout", "error")))
+	assert.Equal(t, "Last message repeated 3 times: message=timeout, level=error\n", ui.OutputWriter.String())
+}
+
+func TestDedupStage_Flush(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	stage, ui := newDedupStageForTest(&now)
+
+	for i := 0; i < 3; i++ {
+		stage.pass(newDedupRow("timeout", "error"))
+		stage.pass(newDedupRow("refused", "error"))
+	}
+	stage.pass(newDedupRow("other", "info"))
+	now = now.Add(11 * time.Second)
+	stage.pass(newDedupRow("refused", "error"))
+	stage.flush(false)
+	assert.Equal(t, "Last message repeated 2 times: message=refused, level=error\n"+
+		"Last message repeated 2 times: message=timeout, level=error\n", ui.OutputWriter.String())
+	assert.Len(t, stage.bursts, 1)
+
+	stage.pass(newDedupRow("refused", "error"))
+	stage.flush(true)
+	assert.Equal(t, "Last message repeated 2 times: message=refused, level=error\n"+
+		"Last message repeated 2 times: message=timeout, level=error\n"+
+		"Last message repeated 1 times: message=refused, level=error\n", ui.OutputWriter.String())
+	assert.Len(t, stage.bursts, 0)
+}
+
+func TestDedupParams_Resolve(t *testing.T) {
+	actual, err := dedupParams{fieldsList: " message, ,level ", window: time.Second}.resolve()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"message", "level"}, actual.fields)
+
+	_, err = dedupParams{enabled: true, fieldsList: "", window: time.Second}.resolve()
+	assert.NotNil(t, err)
+
+	_, err = dedupParams{enabled: true, fieldsList: "message", window: 0}.resolve()
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,279 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/mitchellh/cli"
+	"github.com/voronelf/logview/core"
+	"github.com/voronelf/logview/merge"
+	"github.com/voronelf/logview/pattern"
+	"sort"
+	"strings"
+	"time"
+)
+
+// patternsHelp describes options -mf, -top and -sim of patterns mining
+const patternsHelp = `    -mf field      Field of message for patterns, 'message' by default.
+    -top n         Count of most frequent patterns in report, 20 by default, 0 shows all.
+    -sim value     Minimal similarity of message and pattern from 0 to 1, 0.4 by default.
+`
+
+type Patterns struct {
+	ShutdownCh    <-chan struct{}
+	RowProvider   core.RowProvider   `inject:"RowProvider"`
+	FilterFactory core.FilterFactory `inject:"FilterFactory"`
+	Formatter     core.Formatter     `inject:"FormatterCliColor"`
+	Ui            cli.Ui             `inject:"CliUi"`
+}
+
+var _ cli.Command = (*Patterns)(nil)
+
+func (c *Patterns) Run(args []string) int {
+	var filePath, filterCondition string
+	var bytesCount int64
+	mergeParams := merge.DefaultParams()
+	readParams := core.DefaultReadParams()
+	filterParams := core.DefaultFilterParams()
+	var timezone, levelFields, fieldsOrder string
+	var ptParams patternParams
+	cmdFlags := flag.NewFlagSet("patterns", flag.ContinueOnError)
+	cmdFlags.StringVar(&filePath, "f", "", "")
+	cmdFlags.StringVar(&filterCondition, "c", "", "")
+	cmdFlags.BoolVar(&filterParams.SearchFieldNames, "sn", filterParams.SearchFieldNames, "")
+	cmdFlags.BoolVar(&filterParams.CaseSensitive, "cs", filterParams.CaseSensitive, "")
+	cmdFlags.StringVar(&timezone, "tz", "", "")
+	cmdFlags.StringVar(&levelFields, "lf", "", "")
+	ptParams.addFlags(cmdFlags)
+	cmdFlags.Int64Var(&bytesCount, "b", 0, "")
+	cmdFlags.StringVar(&readParams.Encoding, "e", readParams.Encoding, "")
+	cmdFlags.StringVar(&mergeParams.TimeField, "tf", mergeParams.TimeField, "")
+	cmdFlags.StringVar(&mergeParams.TimeLayout, "tl", mergeParams.TimeLayout, "")
+	cmdFlags.DurationVar(&mergeParams.Window, "w", mergeParams.Window, "")
+	cmdFlags.StringVar(&fieldsOrder, "order", "", "")
+	err := cmdFlags.Parse(args)
+	if err != nil {
+		return cli.RunResultHelp
+	}
+	if filePath == "" {
+		return cli.RunResultHelp
+	}
+	filterParams.TimeField = mergeParams.TimeField
+	filterParams.Location, err = parseTimezone(timezone)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	filterParams.LevelFields = splitFields(levelFields)
+	formatParams := core.DefaultFormatParams()
+	formatParams.LevelFields = filterParams.LevelFields
+	formatParams.OriginalOrder, err = parseFieldsOrder(fieldsOrder)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	ptParams.timeField = mergeParams.TimeField
+	ptParams, err = ptParams.resolve()
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	filter, err := c.FilterFactory.NewFilter(filterCondition, filterParams)
+	if err != nil {
+		c.Ui.Error(formatFilterError(err))
+		return 1
+	}
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+	rowsChan, err := readFilesTail(ctx, c.RowProvider, filePath, bytesCount, readParams, mergeParams)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	printer := newPatternsPrinter(c.Ui, c.Formatter, filter, formatParams, ptParams)
+	return printRows(rowsChan, c.ShutdownCh, printer, []rowFlusher{printer})
+}
+
+func (*Patterns) Synopsis() string {
+	return "Cluster messages of rows matched by filter condition into patterns and show count of every pattern. Args: -f filePath [-b bytes] [-c condition] [options]"
+}
+
+func (*Patterns) Help() string {
+	text := `
+Usage: logview patterns -f filePath [-b bytes] [-c condition] [options]
+
+    Analyze last b bytes from log files, cluster messages of rows matched by filter condition
+    into patterns and show patterns from most frequent. Variable parts of messages, like
+    numbers and ids, are replaced by '<*>'. Every pattern is shown with count of rows,
+    time of first and last row and example row.
+
+Options:
+
+    -f filePath    Log file path, required. Substring '@today@' will be replace
+                   to today date in format 2017-09-28. Can be a glob pattern
+                   or comma-separated list of paths.
+    -b bytes       Count of bytes to last rows in every file for analyzing
+    -e encoding    Encoding of files: auto, utf-8, utf-16le, utf-16be. Default is 'auto',
+                   it is detected by byte order mark. Invalid UTF-8 sequences are replaced.
+    -tf timeField  Name of time field for merging several files, for '_time' in condition
+                   and for time of patterns, 'time' by default
+    -tl timeLayout Layout of time field in Go format, RFC3339 by default.
+                   Numeric values are treated as unix timestamps.
+    -w window      Reorder window for rows written out of order, '1s' by default
+` + patternsHelp + conditionHelp + filterParamsHelp + orderHelp
+	return strings.TrimSpace(text)
+}
+
+// patternParams are options of patterns mining
+type patternParams struct {
+	messageField string
+	top          int
+	similarity   float64
+	timeField    string
+	// interval of live reports, only final report is shown if it is zero
+	interval time.Duration
+}
+
+func (p *patternParams) addFlags(cmdFlags *flag.FlagSet) {
+	cmdFlags.StringVar(&p.messageField, "mf", "message", "")
+	cmdFlags.IntVar(&p.top, "top", 20, "")
+	cmdFlags.Float64Var(&p.similarity, "sim", pattern.DefaultParams().Similarity, "")
+}
+
+// resolve checks values of options
+func (p patternParams) resolve() (patternParams, error) {
+	if p.messageField == "" {
+		return p, errors.New("message field is empty")
+	}
+	if p.top < 0 || p.similarity < 0 || p.similarity > 1 || p.interval < 0 {
+		return p, errors.New("invalid value of patterns option")
+	}
+	return p, nil
+}
+
+// newPatternsPrinter creates printer of patterns report, params must be resolved
+func newPatternsPrinter(ui cli.Ui, formatter core.Formatter, filter core.Filter, formatParams core.FormatParams, params patternParams) *patternsPrinter {
+	minerParams := pattern.DefaultParams()
+	minerParams.Similarity = params.similarity
+	now := time.Now
+	return &patternsPrinter{
+		ui:           ui,
+		formatter:    formatter,
+		filter:       filter,
+		formatParams: formatParams,
+		params:       params,
+		miner:        pattern.NewMiner(minerParams),
+		stats:        map[int]*patternStats{},
+		now:          now,
+		lastReport:   now(),
+	}
+}
+
+// patternsPrinter clusters messages of matched rows and prints report of patterns.
+// Report is printed periodically in live mode and at the end.
+type patternsPrinter struct {
+	ui           cli.Ui
+	formatter    core.Formatter
+	filter       core.Filter
+	formatParams core.FormatParams
+	params       patternParams
+	miner        *pattern.Miner
+	// stats are statistics of patterns by id of cluster
+	stats      map[int]*patternStats
+	now        func() time.Time
+	lastReport time.Time
+}
+
+type patternStats struct {
+	first   string
+	last    string
+	example core.Row
+	// reported is count of rows at last report
+	reported int
+}
+
+func (p *patternsPrinter) print(row core.Row) {
+	if row.Err != nil {
+		p.ui.Error(row.Err.Error())
+		return
+	}
+	if !p.filter.Match(row) {
+		return
+	}
+	message, ok := row.Data[p.params.messageField]
+	if !ok || message == nil {
+		return
+	}
+	cluster := p.miner.Add(fmt.Sprint(message))
+	rowTime := p.rowTime(row)
+	stats, ok := p.stats[cluster.ID]
+	if !ok {
+		stats = &patternStats{first: rowTime, example: row}
+		p.stats[cluster.ID] = stats
+	}
+	stats.last = rowTime
+}
+
+// rowTime returns value of time field or current time, if row has no time
+func (p *patternsPrinter) rowTime(row core.Row) string {
+	if value, ok := row.Data[p.params.timeField]; ok && value != nil {
+		return fmt.Sprint(value)
+	}
+	return p.now().Format(time.RFC3339)
+}
+
+// flush prints report, if interval of live mode is passed or rows are ended
+func (p *patternsPrinter) flush(final bool) {
+	if !final && (p.params.interval == 0 || p.now().Sub(p.lastReport) < p.params.interval) {
+		return
+	}
+	p.lastReport = p.now()
+	p.report(final)
+}
+
+// report prints patterns from most frequent, in live mode patterns are ordered by count of new rows
+func (p *patternsPrinter) report(final bool) {
+	live := p.params.interval > 0
+	clusters := append([]*pattern.Cluster{}, p.miner.Clusters()...)
+	total, added := 0, 0
+	for _, cluster := range clusters {
+		total += cluster.Count
+		added += cluster.Count - p.stats[cluster.ID].reported
+	}
+	if live && !final && added == 0 {
+		return
+	}
+	sort.SliceStable(clusters, func(i, j int) bool {
+		if live {
+			addedI := clusters[i].Count - p.stats[clusters[i].ID].reported
+			addedJ := clusters[j].Count - p.stats[clusters[j].ID].reported
+			if addedI != addedJ {
+				return addedI > addedJ
+			}
+		}
+		return clusters[i].Count > clusters[j].Count
+	})
+	p.ui.Output(fmt.Sprintf("Patterns: %d, rows: %d\n", len(clusters), total))
+	for i, cluster := range clusters {
+		if p.params.top > 0 && i >= p.params.top {
+			break
+		}
+		stats := p.stats[cluster.ID]
+		line := fmt.Sprintf("%8d  %s", cluster.Count, cluster.Template())
+		if live {
+			mark := fmt.Sprintf("+%d", cluster.Count-stats.reported)
+			if stats.reported == 0 {
+				mark = "new"
+			}
+			line = fmt.Sprintf("%8d %6s  %s", cluster.Count, mark, cluster.Template())
+		}
+		p.ui.Output(line)
+		p.ui.Output(fmt.Sprintf("          first: %s, last: %s", stats.first, stats.last))
+		p.ui.Output(p.formatter.Format(stats.example, p.formatParams))
+	}
+	for _, cluster := range clusters {
+		p.stats[cluster.ID].reported = cluster.Count
+	}
+}
@@ -0,0 +1,178 @@
+package command
+
+import (
+	"bytes"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/voronelf/logview/core"
+	"testing"
+	"time"
+)
+
+func newPatternsForTest() (*Patterns, chan<- struct{}) {
+	shutdownCh := make(chan struct{})
+	return &Patterns{
+		RowProvider:   &core.MockRowProvider{},
+		FilterFactory: &core.MockFilterFactory{},
+		Formatter:     &core.MockFormatter{},
+		Ui:            &cli.MockUi{},
+		ShutdownCh:    shutdownCh,
+	}, shutdownCh
+}
+
+func newPatternsPrinterForTest(params patternParams, now *time.Time) (*patternsPrinter, *cli.MockUi) {
+	mockFilter := &core.MockFilter{}
+	mockFilter.On("Match", mock.Anything).Return(func(row core.Row) bool {
+		return row.Data["skip"] == nil
+	})
+	mockFormatter := &core.MockFormatter{}
+	mockFormatter.On("Format", mock.Anything, core.DefaultFormatParams()).Return(func(row core.Row, _ core.FormatParams) string {
+		return "example: " + row.Data["message"].(string)
+	})
+	ui := &cli.MockUi{OutputWriter: &bytes.Buffer{}, ErrorWriter: &bytes.Buffer{}}
+	params.messageField = "message"
+	params.timeField = "time"
+	params.similarity = 0.4
+	printer := newPatternsPrinter(ui, mockFormatter, mockFilter, core.DefaultFormatParams(), params)
+	printer.now = func() time.Time {
+		return *now
+	}
+	printer.lastReport = *now
+	return printer, ui
+}
+
+func newPatternRow(time, message string) core.Row {
+	return core.Row{Data: map[string]interface{}{"time": time, "message": message}}
+}
+
+func TestPatternsPrinter_Report(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	printer, ui := newPatternsPrinterForTest(patternParams{top: 2}, &now)
+
+	printer.print(newPatternRow("10:00", "user alice logged in"))
+	printer.print(newPatternRow("10:01", "connection to 10.0.0.1 refused"))
+	printer.print(newPatternRow("10:02", "connection to 10.0.0.2 refused"))
+	printer.print(newPatternRow("10:03", "user bob logged in"))
+	printer.print(newPatternRow("10:04", "connection to 10.0.0.3 refused"))
+	printer.print(newPatternRow("10:05", "cache miss"))
+	printer.print(core.Row{Data: map[string]interface{}{"time": "10:06", "message": "cache miss", "skip": true}})
+	printer.print(core.Row{Data: map[string]interface{}{"time": "10:07", "text": "no message"}})
+	printer.flush(false)
+	assert.Equal(t, "", ui.OutputWriter.String())
+
+	printer.flush(true)
+	expected := "Patterns: 3, rows: 6\n\n" +
+		"       3  connection to <*> refused\n" +
+		"          first: 10:01, last: 10:04\n" +
+		"example: connection to 10.0.0.1 refused\n" +
+		"       2  user <*> logged in\n" +
+		"          first: 10:00, last: 10:03\n" +
+		"example: user alice logged in\n"
+	assert.Equal(t, expected, ui.OutputWriter.String())
+}
+
+func TestPatternsPrinter_Report_Live(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	printer, ui := newPatternsPrinterForTest(patternParams{interval: 10 * time.Second}, &now)
+
+	printer.print(newPatternRow("10:00", "connection to 10.0.0.1 refused"))
+	printer.print(newPatternRow("10:01", "connection to 10.0.0.2 refused"))
+	printer.print(core.Row{Data: map[string]interface{}{"message": "cache miss"}})
+	now = now.Add(5 * time.Second)
+	printer.flush(false)
+	assert.Equal(t, "", ui.OutputWriter.String())
+
+	now = now.Add(5 * time.Second)
+	printer.flush(false)
+	expected := "Patterns: 2, rows: 3\n\n" +
+		"       2    new  connection to <*> refused\n" +
+		"          first: 10:00, last: 10:01\n" +
+		"example: connection to 10.0.0.1 refused\n" +
+		"       1    new  cache miss\n" +
+		"          first: 2024-05-01T10:00:00Z, last: 2024-05-01T10:00:00Z\n" +
+		"example: cache miss\n"
+	assert.Equal(t, expected, ui.OutputWriter.String())
+
+	// report is skipped without new rows
+	ui.OutputWriter.Reset()
+	now = now.Add(10 * time.Second)
+	printer.flush(false)
+	assert.Equal(t, "", ui.OutputWriter.String())
+
+	printer.print(core.Row{Data: map[string]interface{}{"message": "cache miss"}})
+	printer.print(core.Row{Data: map[string]interface{}{"message": "cache miss"}})
+	printer.flush(true)
+	expected = "Patterns: 2, rows: 5\n\n" +
+		"       3     +2  cache miss\n" +
+		"          first: 2024-05-01T10:00:00Z, last: 2024-05-01T10:00:20Z\n" +
+		"example: cache miss\n" +
+		"       2     +0  connection to <*> refused\n" +
+		"          first: 10:00, last: 10:01\n" +
+		"example: connection to 10.0.0.1 refused\n"
+	assert.Equal(t, expected, ui.OutputWriter.String())
+}
+
+func TestPatterns_Run(t *testing.T) {
+	cmd, shutdownCh := newPatternsForTest()
+	defer close(shutdownCh)
+	mockFilterFactory := cmd.FilterFactory.(*core.MockFilterFactory)
+	mockProvider := cmd.RowProvider.(*core.MockRowProvider)
+	mockFormatter := cmd.Formatter.(*core.MockFormatter)
+
+	row1 := newPatternRow("10:00", "user alice logged in")
+	row2 := newPatternRow("10:01", "user bob logged in")
+	channel := make(chan core.Row, 2)
+	channel <- row1
+	channel <- row2
+	close(channel)
+	mockFilter := &core.MockFilter{}
+	mockFilterFactory.On("NewFilter", "someFilter", core.DefaultFilterParams()).Return(mockFilter, nil).Once()
+	mockProvider.On("ReadFileTail", mock.Anything, "someFile", int64(123), core.DefaultReadParams()).Return((<-chan core.Row)(channel), nil).Once()
+	mockFilter.On("Match", mock.Anything).Return(true).Twice()
+	mockFormatter.On("Format", row1, core.DefaultFormatParams()).Return("SomeData").Once()
+
+	cmd.Run([]string{"-f", "someFile", "-b", "123", "-c", "someFilter"})
+
+	mockProvider.AssertExpectations(t)
+	mockFilterFactory.AssertExpectations(t)
+	mockFilter.AssertExpectations(t)
+	mockFormatter.AssertExpectations(t)
+	expected := "Patterns: 1, rows: 2\n\n" +
+		"       2  user <*> logged in\n" +
+		"          first: 10:00, last: 10:01\n" +
+		"SomeData\n"
+	assert.Equal(t, expected, cmd.Ui.(*cli.MockUi).OutputWriter.String())
+}
+
+func TestPatterns_Run_WithoutFile(t *testing.T) {
+	cmd, shutdownCh := newPatternsForTest()
+	defer close(shutdownCh)
+
+	assert.Equal(t, cli.RunResultHelp, cmd.Run([]string{"-c", "someFilter"}))
+}
+
+func TestPatterns_Run_FieldsOrder(t *testing.T) {
+	cmd, shutdownCh := newPatternsForTest()
+	defer close(shutdownCh)
+	mockFilterFactory := cmd.FilterFactory.(*core.MockFilterFactory)
+	mockProvider := cmd.RowProvider.(*core.MockRowProvider)
+	mockFormatter := cmd.Formatter.(*core.MockFormatter)
+
+	row := newPatternRow("10:00", "user alice logged in")
+	channel := make(chan core.Row, 1)
+	channel <- row
+	close(channel)
+	mockFilter := &core.MockFilter{}
+	mockFilterFactory.On("NewFilter", "", core.DefaultFilterParams()).Return(mockFilter, nil).Once()
+	mockProvider.On("ReadFileTail", mock.Anything, "someFile", int64(0), core.DefaultReadParams()).Return((<-chan core.Row)(channel), nil).Once()
+	mockFilter.On("Match", row).Return(true).Once()
+	formatParams := core.DefaultFormatParams()
+	formatParams.OriginalOrder = true
+	mockFormatter.On("Format", row, formatParams).Return("SomeData").Once()
+
+	assert.Equal(t, 0, cmd.Run([]string{"-f", "someFile", "-order", "original"}))
+
+	mockFormatter.AssertExpectations(t)
+	assert.Equal(t, 1, cmd.Run([]string{"-f", "someFile", "-order", "wrong"}))
+}
@@ -0,0 +1,153 @@
+package command
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/mitchellh/cli"
+	"github.com/voronelf/logview/core"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// conditionCheckInterval is default interval of checking changes of condition file
+const conditionCheckInterval = time.Second
+
+const reloadHelp = `    -cf file       Read condition from file instead of -c. Condition is reloaded when file
+                   is changed or on SIGHUP, SIGHUP also reloads macros and lists of values.
+    -prompt        Read new conditions from stdin while watching, one condition per line,
+                   '*' shows all rows. It can't be used, when log is read from stdin.
+                   Invalid condition is reported and current filter is kept.
+`
+
+// swappableFilter delegates matching to filter, which can be replaced while rows are matched
+type swappableFilter struct {
+	current atomic.Value
+}
+
+// filterHolder keeps filters of different types in atomic.Value
+type filterHolder struct {
+	filter core.Filter
+}
+
+var _ core.Filter = (*swappableFilter)(nil)
+
+func newSwappableFilter(filter core.Filter) *swappableFilter {
+	s := &swappableFilter{}
+	s.swap(filter)
+	return s
+}
+
+func (s *swappableFilter) Match(row core.Row) bool {
+	return s.current.Load().(filterHolder).filter.Match(row)
+}
+
+func (s *swappableFilter) swap(filter core.Filter) {
+	s.current.Store(filterHolder{filter: filter})
+}
+
+// filterReloader replaces filter of running command by new conditions from file or prompt,
+// state of printers like buffered context rows is kept
+type filterReloader struct {
+	ui      cli.Ui
+	factory core.FilterFactory
+	params  core.FilterParams
+	filter  *swappableFilter
+	// interval is interval of checking changes of condition file
+	interval time.Duration
+}
+
+// reload creates filter for condition and swaps it, current filter is kept on error
+func (r *filterReloader) reload(condition, source string) bool {
+	filter, err := r.factory.NewFilter(condition, r.params)
+	if err != nil {
+		r.ui.Error(formatFilterError(err))
+		return false
+	}
+	// message is printed before swap, so it is shown before rows matched by new filter
+	r.ui.Output(messageFilterChanged(condition, source))
+	r.filter.swap(filter)
+	return true
+}
+
+// watchFile reloads condition from file in background, when file is changed or on every signal from reloadCh.
+// File is checked every interval. Watching is stopped by shutdownCh, returned channel is closed after that.
+func (r *filterReloader) watchFile(shutdownCh <-chan struct{}, reloadCh <-chan struct{}, path, condition string) <-chan struct{} {
+	doneCh := make(chan struct{})
+	ticker := time.NewTicker(r.interval)
+	go func() {
+		defer close(doneCh)
+		defer ticker.Stop()
+		r.watchChanges(shutdownCh, reloadCh, ticker.C, path, condition)
+	}()
+	return doneCh
+}
+
+// watchChanges reloads condition from file, when it is changed on check from checkCh, or on every signal from reloadCh.
+// Errors of reading are reported only on signal, because editors can replace file by rename.
+func (r *filterReloader) watchChanges(shutdownCh <-chan struct{}, reloadCh <-chan struct{}, checkCh <-chan time.Time, path, condition string) {
+	for {
+		select {
+		case <-shutdownCh:
+			return
+		case <-reloadCh:
+			newCondition, err := readConditionFile(path)
+			if err != nil {
+				r.ui.Error(err.Error())
+				continue
+			}
+			condition = newCondition
+			r.reload(condition, "file")
+		case <-checkCh:
+			newCondition, err := readConditionFile(path)
+			if err != nil || newCondition == condition {
+				continue
+			}
+			// invalid condition is reported once, until file is changed again
+			condition = newCondition
+			r.reload(condition, "file")
+		}
+	}
+}
+
+// readPrompt reads new conditions from input line by line, empty lines are skipped
+func (r *filterReloader) readPrompt(shutdownCh <-chan struct{}, input io.Reader) {
+	scanner := bufio.NewScanner(input)
+	for scanner.Scan() {
+		select {
+		case <-shutdownCh:
+			return
+		default:
+		}
+		condition := strings.TrimSpace(scanner.Text())
+		if condition != "" {
+			r.reload(condition, "prompt")
+		}
+	}
+}
+
+// readConditionFile reads condition from file, lines starting with '#' are comments
+func readConditionFile(path string) (string, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("condition file reading error: %s", err)
+	}
+	lines := []string{}
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, " "), nil
+}
+
+func messageFilterChanged(condition, source string) string {
+	return fmt.Sprintf("\nFilter changed from %s to \"%s\"\n", source, condition)
+}
+
+func messagePrompt() string {
+	return "Type new condition and press Enter to change filter\n"
+}
@@ -0,0 +1,147 @@
+package command
+
+import (
+	"bytes"
+	"errors"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/assert"
+	"github.com/voronelf/logview/core"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newFilterReloaderForTest(filter core.Filter) *filterReloader {
+	return &filterReloader{
+		ui:       &cli.MockUi{OutputWriter: &bytes.Buffer{}, ErrorWriter: &bytes.Buffer{}},
+		factory:  &core.MockFilterFactory{},
+		params:   core.DefaultFilterParams(),
+		filter:   newSwappableFilter(filter),
+		interval: time.Millisecond,
+	}
+}
+
+func writeConditionFileForTest(t *testing.T, content string) (path string, cleanup func()) {
+	dir, err := ioutil.TempDir("", "logview_test_")
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	path = filepath.Join(dir, "condition.txt")
+	if !assert.Nil(t, ioutil.WriteFile(path, []byte(content), 0644)) {
+		t.FailNow()
+	}
+	return path, func() { os.RemoveAll(dir) }
+}
+
+func TestSwappableFilter_Match(t *testing.T) {
+	row := core.Row{Data: map[string]interface{}{"a": "1"}}
+	first := &core.MockFilter{}
+	first.On("Match", row).Return(false).Once()
+	second := &core.MockFilter{}
+	second.On("Match", row).Return(true).Once()
+
+	filter := newSwappableFilter(first)
+	assert.False(t, filter.Match(row))
+	filter.swap(second)
+	assert.True(t, filter.Match(row))
+	first.AssertExpectations(t)
+	second.AssertExpectations(t)
+}
+
+func TestFilterReloader_Reload(t *testing.T) {
+	row := core.Row{Data: map[string]interface{}{"a": "1"}}
+	current := &core.MockFilter{}
+	current.On("Match", row).Return(false)
+	newFilter := &core.MockFilter{}
+	newFilter.On("Match", row).Return(true)
+	reloader := newFilterReloaderForTest(current)
+	mockFactory := reloader.factory.(*core.MockFilterFactory)
+	mockFactory.On("NewFilter", "a: (", core.DefaultFilterParams()).Return(nil, errors.New("some error")).Once()
+	mockFactory.On("NewFilter", "a: 1", core.DefaultFilterParams()).Return(newFilter, nil).Once()
+
+	assert.False(t, reloader.reload("a: (", "prompt"))
+	assert.False(t, reloader.filter.Match(row))
+	assert.True(t, reloader.reload("a: 1", "prompt"))
+	assert.True(t, reloader.filter.Match(row))
+
+	mockUi := reloader.ui.(*cli.MockUi)
+	assert.Equal(t, "some error\n", mockUi.ErrorWriter.String())
+	assert.Equal(t, messageFilterChanged("a: 1", "prompt")+"\n", mockUi.OutputWriter.String())
+}
+
+func TestFilterReloader_WatchFile(t *testing.T) {
+	path, cleanup := writeConditionFileForTest(t, "a: 1")
+	defer cleanup()
+	reloader := newFilterReloaderForTest(&core.MockFilter{})
+	reloader.interval = time.Hour
+
+	shutdownCh := make(chan struct{})
+	doneCh := reloader.watchFile(shutdownCh, nil, path, "a: 1")
+	close(shutdownCh)
+	select {
+	case <-doneCh:
+	case <-time.After(time.Second):
+		t.Fatal("watching of file isn't stopped")
+	}
+}
+
+func TestFilterReloader_WatchChanges(t *testing.T) {
+	path, cleanup := writeConditionFileForTest(t, "a: 1")
+	defer cleanup()
+	reloader := newFilterReloaderForTest(&core.MockFilter{})
+	mockFactory := reloader.factory.(*core.MockFilterFactory)
+	mockFactory.On("NewFilter", "a: 2", core.DefaultFilterParams()).Return(&core.MockFilter{}, nil).Twice()
+
+	shutdownCh := make(chan struct{})
+	reloadCh := make(chan struct{})
+	checkCh := make(chan time.Time)
+	doneCh := make(chan struct{})
+	go func() {
+		defer close(doneCh)
+		reloader.watchChanges(shutdownCh, reloadCh, checkCh, path, "a: 1")
+	}()
+	// every send is received only after previous signal is handled
+	checkCh <- time.Now()
+	ioutil.WriteFile(path, []byte("a: 2"), 0644)
+	checkCh <- time.Now()
+	// signal reloads condition even without changes
+	reloadCh <- struct{}{}
+	checkCh <- time.Now()
+	os.Remove(path)
+	checkCh <- time.Now()
+	reloadCh <- struct{}{}
+	close(shutdownCh)
+	<-doneCh
+
+	mockFactory.AssertExpectations(t)
+	mockUi := reloader.ui.(*cli.MockUi)
+	assert.Equal(t, strings.Repeat(messageFilterChanged("a: 2", "file")+"\n", 2), mockUi.OutputWriter.String())
+	assert.Equal(t, 1, strings.Count(mockUi.ErrorWriter.String(), "condition file reading error"))
+}
+
+func TestFilterReloader_ReadPrompt(t *testing.T) {
+	reloader := newFilterReloaderForTest(&core.MockFilter{})
+	mockFactory := reloader.factory.(*core.MockFilterFactory)
+	mockFactory.On("NewFilter", "a: 1", core.DefaultFilterParams()).Return(&core.MockFilter{}, nil).Once()
+	mockFactory.On("NewFilter", "*", core.DefaultFilterParams()).Return(&core.MockFilter{}, nil).Once()
+
+	reloader.readPrompt(make(chan struct{}), strings.NewReader(" a: 1 \n\n*\n"))
+
+	mockFactory.AssertExpectations(t)
+	expected := messageFilterChanged("a: 1", "prompt") + "\n" + messageFilterChanged("*", "prompt") + "\n"
+	assert.Equal(t, expected, reloader.ui.(*cli.MockUi).OutputWriter.String())
+}
+
+func TestReadConditionFile(t *testing.T) {
+	path, cleanup := writeConditionFileForTest(t, "# errors of api\nmodule: api\n  and level: error\n\n")
+	defer cleanup()
+	condition, err := readConditionFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "module: api and level: error", condition)
+
+	_, err = readConditionFile(path + ".not-exists")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,288 @@
+package command
+
+import (
+	"errors"
+	"flag"
+	"github.com/mitchellh/cli"
+	"github.com/voronelf/logview/core"
+	"time"
+)
+
+// contextSeparator is printed between groups of context rows, which are not adjacent
+const contextSeparator = "--"
+
+// contextHelp describes options -A, -B and -C of context rows around matched rows
+const contextHelp = `    -A count       Show count rows after every matched row.
+    -B count       Show count rows before every matched row.
+    -C count       Show count rows before and after every matched row, if -A or -B is not set.
+`
+
+// contextParams are counts of rows shown around matched rows
+type contextParams struct {
+	before  int
+	after   int
+	context int
+}
+
+func (p *contextParams) addFlags(cmdFlags *flag.FlagSet) {
+	cmdFlags.IntVar(&p.after, "A", -1, "")
+	cmdFlags.IntVar(&p.before, "B", -1, "")
+	cmdFlags.IntVar(&p.context, "C", 0, "")
+}
+
+// resolve applies -C count to counts, which are not set
+func (p contextParams) resolve() (contextParams, error) {
+	if p.context < 0 || p.before < -1 || p.after < -1 {
+		return p, errors.New("count of context rows can't be negative")
+	}
+	if p.before < 0 {
+		p.before = p.context
+	}
+	if p.after < 0 {
+		p.after = p.context
+	}
+	return p, nil
+}
+
+// flushInterval is interval of flushing rows and summaries, which are held by stages of printing
+const flushInterval = time.Second
+
+// printParams are options of printing matched rows: context rows, trace mode, suppression of duplicates and sampling
+type printParams struct {
+	context contextParams
+	trace   traceParams
+	dedup   dedupParams
+	sample  sampleParams
+}
+
+func (p *printParams) addFlags(cmdFlags *flag.FlagSet) {
+	p.context.addFlags(cmdFlags)
+	p.trace.addFlags(cmdFlags)
+	p.dedup.addFlags(cmdFlags)
+	p.sample.addFlags(cmdFlags)
+}
+
+// resolve resolves params of every mode and checks that params of different modes are not mixed
+func (p printParams) resolve() (printParams, error) {
+	var err error
+	p.context, err = p.context.resolve()
+	if err != nil {
+		return p, err
+	}
+	p.trace, err = p.trace.resolve()
+	if err != nil {
+		return p, err
+	}
+	p.dedup, err = p.dedup.resolve()
+	if err != nil {
+		return p, err
+	}
+	p.sample, err = p.sample.resolve()
+	if err != nil {
+		return p, err
+	}
+	if p.trace.enabled() && (p.context.before > 0 || p.context.after > 0) {
+		return p, errors.New("context rows can't be used in trace mode")
+	}
+	return p, nil
+}
+
+// rowPrinter shows rows selected by it
+type rowPrinter interface {
+	print(row core.Row)
+}
+
+// rowStage selects matched rows for output, e.g. suppresses duplicates.
+// Matched rows, which are not passed by stage, are hidden completely, they aren't shown as context rows too.
+type rowStage interface {
+	pass(row core.Row) bool
+}
+
+// matchResult is result of matching of row by rowMatcher
+type matchResult int
+
+const (
+	rowNotMatched matchResult = iota
+	rowMatched
+	// rowSuppressed is row, which is matched by filter, but isn't passed by stage
+	rowSuppressed
+)
+
+// rowMatcher matches rows by filter and passes matched rows through stages of output
+type rowMatcher struct {
+	filter core.Filter
+	stages []rowStage
+}
+
+func (m rowMatcher) match(row core.Row) matchResult {
+	if !m.filter.Match(row) {
+		return rowNotMatched
+	}
+	for _, stage := range m.stages {
+		if !stage.pass(row) {
+			return rowSuppressed
+		}
+	}
+	return rowMatched
+}
+
+// rowFlusher holds rows or summaries and prints them later.
+// Flush is called periodically and once with final true, when rows are ended.
+type rowFlusher interface {
+	flush(final bool)
+}
+
+// newPrinter creates printer of trace mode or printer of matched rows with context, params must be resolved.
+// Matched rows are passed through stages, which are enabled, flushers of stages and of trace printer are returned.
+func newPrinter(ui cli.Ui, formatter core.Formatter, filter core.Filter, formatParams core.FormatParams, params printParams) (rowPrinter, []rowFlusher) {
+	matcher := rowMatcher{filter: filter}
+	var flushers []rowFlusher
+	if params.dedup.enabled {
+		dedup := newDedupStage(ui, params.dedup)
+		matcher.stages = append(matcher.stages, dedup)
+		flushers = append(flushers, dedup)
+	}
+	if params.sample.enabled() {
+		sample := newSampleStage(ui, params.sample)
+		matcher.stages = append(matcher.stages, sample)
+		flushers = append(flushers, sample)
+	}
+	if params.trace.enabled() {
+		// groups of traced rows are printed before summaries of stages
+		printer := newTracePrinter(ui, formatter, matcher, formatParams, params.trace)
+		return printer, append([]rowFlusher{printer}, flushers...)
+	}
+	return newRowsPrinter(ui, formatter, matcher, formatParams, params.context), flushers
+}
+
+// newRowsPrinter creates printer of matched rows, ctxParams must be resolved
+func newRowsPrinter(ui cli.Ui, formatter core.Formatter, matcher rowMatcher, formatParams core.FormatParams, ctxParams contextParams) *rowsPrinter {
+	return &rowsPrinter{
+		ui:           ui,
+		formatter:    formatter,
+		matcher:      matcher,
+		formatParams: formatParams,
+		after:        ctxParams.after,
+		before:       newRowsRing(ctxParams.before),
+		lastPrinted:  -1,
+	}
+}
+
+// rowsPrinter prints matched rows and context rows around them
+type rowsPrinter struct {
+	ui           cli.Ui
+	formatter    core.Formatter
+	matcher      rowMatcher
+	formatParams core.FormatParams
+	after        int
+	// before keeps last rows, which are not printed yet
+	before *rowsRing
+	// afterLeft is count of rows, which will be printed after last matched row
+	afterLeft   int
+	index       int
+	lastPrinted int
+}
+
+func (p *rowsPrinter) print(row core.Row) {
+	if row.Err != nil {
+		p.ui.Error(row.Err.Error())
+		return
+	}
+	result := p.matcher.match(row)
+	if result == rowSuppressed {
+		return
+	}
+	p.index++
+	if result == rowMatched {
+		firstIndex := p.index - p.before.len()
+		if p.lastPrinted >= 0 && firstIndex > p.lastPrinted+1 && (p.after > 0 || p.before.size() > 0) {
+			p.ui.Output(contextSeparator)
+		}
+		p.before.drain(p.output)
+		p.output(row)
+		p.afterLeft = p.after
+		return
+	}
+	if p.afterLeft > 0 {
+		p.afterLeft--
+		p.output(row)
+		return
+	}
+	p.before.push(row)
+}
+
+func (p *rowsPrinter) output(row core.Row) {
+	p.ui.Output(p.formatter.Format(row, p.formatParams))
+	p.lastPrinted = p.index
+}
+
+// printRows prints rows from channel until it is closed or shutdown is requested
+func printRows(rowsChan <-chan core.Row, shutdownCh <-chan struct{}, printer rowPrinter, flushers []rowFlusher) int {
+	var tick <-chan time.Time
+	if len(flushers) > 0 {
+		ticker := time.NewTicker(flushInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	defer func() {
+		for _, flusher := range flushers {
+			flusher.flush(true)
+		}
+	}()
+	for {
+		select {
+		case row, ok := <-rowsChan:
+			if !ok {
+				return 0
+			}
+			printer.print(row)
+		case <-tick:
+			for _, flusher := range flushers {
+				flusher.flush(false)
+			}
+		case <-shutdownCh:
+			return 0
+		}
+	}
+}
+
+func newRowsRing(size int) *rowsRing {
+	return &rowsRing{rows: make([]core.Row, size)}
+}
+
+// rowsRing keeps last rows, the oldest row is replaced when ring is full
+type rowsRing struct {
+	rows  []core.Row
+	start int
+	count int
+}
+
+func (r *rowsRing) size() int {
+	return len(r.rows)
+}
+
+func (r *rowsRing) len() int {
+	return r.count
+}
+
+func (r *rowsRing) push(row core.Row) {
+	if len(r.rows) == 0 {
+		return
+	}
+	if r.count < len(r.rows) {
+		r.rows[(r.start+r.count)%len(r.rows)] = row
+		r.count++
+		return
+	}
+	r.rows[r.start] = row
+	r.start = (r.start + 1) % len(r.rows)
+}
+
+// drain calls fn for kept rows from the oldest one and empties ring
+func (r *rowsRing) drain(fn func(row core.Row)) {
+	for ; r.count > 0; r.count-- {
+		fn(r.rows[r.start])
+		r.rows[r.start] = core.Row{}
+		r.start = (r.start + 1) % len(r.rows)
+	}
+}
@@ -0,0 +1,182 @@
+package command
+
+import (
+	"bytes"
+	"errors"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/voronelf/logview/core"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newRowsPrinterForTest creates printer, which matches rows with field 'match' and outputs field 'n'
+func newRowsPrinterForTest(ctxParams contextParams) (*rowsPrinter, *cli.MockUi) {
+	mockFilter := &core.MockFilter{}
+	mockFilter.On("Match", mock.Anything).Return(func(row core.Row) bool {
+		return row.Data["match"] == true
+	})
+	mockFormatter := &core.MockFormatter{}
+	mockFormatter.On("Format", mock.Anything, core.DefaultFormatParams()).Return(func(row core.Row, _ core.FormatParams) string {
+		return row.Data["n"].(string)
+	})
+	ui := &cli.MockUi{OutputWriter: &bytes.Buffer{}, ErrorWriter: &bytes.Buffer{}}
+	return newRowsPrinter(ui, mockFormatter, rowMatcher{filter: mockFilter}, core.DefaultFormatParams(), ctxParams), ui
+}
+
+func TestRowsPrinter_Print(t *testing.T) {
+	cases := []struct {
+		rows     string
+		before   int
+		after    int
+		expected string
+	}{
+		{"..x..x..", 0, 0, "2 5"},
+		{"........", 1, 1, ""},
+		{"..x.....", 1, 1, "1 2 3"},
+		{"..x.....", 5, 0, "0 1 2"},
+		{"..x.....", 0, 10, "2 3 4 5 6 7"},
+		{"x.....x.", 1, 1, "0 1 -- 5 6 7"},
+		{"x...x...", 1, 1, "0 1 -- 3 4 5"},
+		{"x..x....", 1, 1, "0 1 2 3 4"},
+		{"x.x.x...", 1, 0, "0 1 2 3 4"},
+		{"xx...xx.", 0, 0, "0 1 5 6"},
+		{"x...x.x.", 0, 1, "0 1 -- 4 5 6 7"},
+		{".....x..x.", 2, 0, "3 4 5 6 7 8"},
+		{"......x...x", 2, 0, "4 5 6 -- 8 9 10"},
+	}
+	for i, cs := range cases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			printer, ui := newRowsPrinterForTest(contextParams{before: cs.before, after: cs.after})
+			for n, symbol := range cs.rows {
+				printer.print(core.Row{Data: map[string]interface{}{"n": strconv.Itoa(n), "match": symbol == 'x'}})
+			}
+			assert.Equal(t, cs.expected, strings.TrimSpace(strings.Replace(ui.OutputWriter.String(), "\n", " ", -1)))
+		})
+	}
+}
+
+func TestRowsPrinter_Print_Error(t *testing.T) {
+	printer, ui := newRowsPrinterForTest(contextParams{before: 1, after: 1})
+	printer.print(core.Row{Data: map[string]interface{}{"n": "0", "match": false}})
+	printer.print(core.Row{Err: errors.New("some error")})
+	printer.print(core.Row{Data: map[string]interface{}{"n": "1", "match": true}})
+
+	assert.Equal(t, "some error\n", ui.ErrorWriter.String())
+	assert.Equal(t, "0\n1\n", ui.OutputWriter.String())
+}
+
+func TestContextParams_Resolve(t *testing.T) {
+	cases := []struct {
+		params   contextParams
+		expected contextParams
+		err      bool
+	}{
+		{contextParams{before: -1, after: -1}, contextParams{}, false},
+		{contextParams{before: -1, after: -1, context: 3}, contextParams{before: 3, after: 3, context: 3}, false},
+		{contextParams{before: 1, after: -1, context: 3}, contextParams{before: 1, after: 3, context: 3}, false},
+		{contextParams{before: -1, after: 0, context: 3}, contextParams{before: 3, after: 0, context: 3}, false},
+		{contextParams{before: -1, after: -1, context: -2}, contextParams{}, true},
+		{contextParams{before: -2, after: -1}, contextParams{}, true},
+	}
+	for i, cs := range cases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			actual, err := cs.params.resolve()
+			if cs.err {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, cs.expected, actual)
+		})
+	}
+}
+
+func TestPrintParams_Resolve(t *testing.T) {
+	params := printParams{
+		context: contextParams{before: -1, after: -1, context: 2},
+		trace:   traceParams{window: 10},
+		dedup:   dedupParams{fieldsList: "message", window: time.Second},
+	}
+	actual, err := params.resolve()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, actual.context.before)
+	assert.Equal(t, []string{"message"}, actual.dedup.fields)
+
+	params.trace.fieldsList = "request_id"
+	_, err = params.resolve()
+	assert.NotNil(t, err)
+
+	params.context.context = 0
+	actual, err = params.resolve()
+	assert.Nil(t, err)
+	assert.True(t, actual.trace.enabled())
+}
+
+func TestPrintRows(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	dedup, ui := newDedupStageForTest(&now)
+	mockFilter := &core.MockFilter{}
+	mockFilter.On("Match", mock.Anything).Return(true)
+	mockFormatter := &core.MockFormatter{}
+	mockFormatter.On("Format", mock.Anything, core.DefaultFormatParams()).Return(func(row core.Row, _ core.FormatParams) string {
+		return row.Data["message"].(string)
+	})
+	matcher := rowMatcher{filter: mockFilter, stages: []rowStage{dedup}}
+	printer := newRowsPrinter(ui, mockFormatter, matcher, core.DefaultFormatParams(), contextParams{})
+	rowsChan := make(chan core.Row, 3)
+	rowsChan <- newDedupRow("timeout", "error")
+	rowsChan <- newDedupRow("timeout", "error")
+	rowsChan <- newDedupRow("refused", "error")
+	close(rowsChan)
+
+	assert.Equal(t, 0, printRows(rowsChan, nil, printer, []rowFlusher{dedup}))
+	assert.Equal(t, "timeout\nrefused\nLast message repeated 1 times: message=timeout, level=error\n", ui.OutputWriter.String())
+}
+
+func TestNewPrinter_SampleWithContext(t *testing.T) {
+	mockFilter := &core.MockFilter{}
+	mockFilter.On("Match", mock.Anything).Return(true)
+	mockFormatter := &core.MockFormatter{}
+	mockFormatter.On("Format", mock.Anything, core.DefaultFormatParams()).Return(func(row core.Row, _ core.FormatParams) string {
+		return row.Data["n"].(string)
+	})
+	ui := &cli.MockUi{OutputWriter: &bytes.Buffer{}, ErrorWriter: &bytes.Buffer{}}
+	params, err := printParams{context: contextParams{before: -1, after: -1, context: 2}, sample: sampleParams{every: 5}}.resolve()
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	printer, flushers := newPrinter(ui, mockFormatter, mockFilter, core.DefaultFormatParams(), params)
+	for n := 0; n < 10; n++ {
+		printer.print(core.Row{Data: map[string]interface{}{"n": strconv.Itoa(n)}})
+	}
+	for _, flusher := range flushers {
+		flusher.flush(true)
+	}
+	// rows skipped by sampling are not shown as context rows
+	assert.Equal(t, "0\n5\nSampling skipped 8 rows\n", ui.OutputWriter.String())
+}
+
+// passStage passes rows without field 'suppress'
+type passStage struct{}
+
+func (passStage) pass(row core.Row) bool {
+	return row.Data["suppress"] == nil
+}
+
+func TestRowsPrinter_Print_Suppressed(t *testing.T) {
+	printer, ui := newRowsPrinterForTest(contextParams{before: 1, after: 1})
+	printer.matcher.stages = []rowStage{passStage{}}
+	// suppressed rows are not shown as context rows and don't break adjacency of rows
+	for n, symbol := range "..xs.s.x.s" {
+		data := map[string]interface{}{"n": strconv.Itoa(n), "match": symbol != '.'}
+		if symbol == 's' {
+			data["suppress"] = true
+		}
+		printer.print(core.Row{Data: data})
+	}
+	assert.Equal(t, "1 2 4 6 7 8", strings.TrimSpace(strings.Replace(ui.OutputWriter.String(), "\n", " ", -1)))
+}
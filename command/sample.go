@@ -0,0 +1,210 @@
+package command
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/mitchellh/cli"
+	"github.com/voronelf/logview/core"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+)
+
+// sampleSummary is printed periodically and at the end, if matched rows were skipped by sampling
+const sampleSummary = "Sampling skipped %d rows"
+
+// sampleStateExpiration is time without rows, after which state of key is removed
+const sampleStateExpiration = time.Minute
+
+// sampleHelp describes options -sample, -sp, -rate, -sk and -si of sampling
+const sampleHelp = `    -sample n      Show only every n-th matched row.
+    -sp p          Show matched row with probability p from 0 to 1, e.g. 0.1.
+    -rate n        Show not more than n matched rows per second.
+    -sk fields     Comma-separated list of key fields for sampling, e.g. 'module'.
+                   Rows with different values of key fields are sampled separately.
+    -si interval   Interval of summary of skipped rows, e.g. '1m'. By default
+                   summary is shown only at the end.
+`
+
+// sampleParams are options of sampling, only one of every, probability and rate can be set
+type sampleParams struct {
+	every       int
+	probability float64
+	rate        float64
+	fieldsList  string
+	fields      []string
+	interval    time.Duration
+}
+
+func (p *sampleParams) addFlags(cmdFlags *flag.FlagSet) {
+	cmdFlags.IntVar(&p.every, "sample", 0, "")
+	cmdFlags.Float64Var(&p.probability, "sp", 0, "")
+	cmdFlags.Float64Var(&p.rate, "rate", 0, "")
+	cmdFlags.StringVar(&p.fieldsList, "sk", "", "")
+	cmdFlags.DurationVar(&p.interval, "si", 0, "")
+}
+
+// resolve parses list of key fields and checks values
+func (p sampleParams) resolve() (sampleParams, error) {
+	p.fields = splitFields(p.fieldsList)
+	if p.every < 0 || p.probability < 0 || p.probability > 1 || p.rate < 0 || p.interval < 0 {
+		return p, errors.New("invalid value of sampling option")
+	}
+	modes := 0
+	for _, enabled := range []bool{p.every > 0, p.probability > 0, p.rate > 0} {
+		if enabled {
+			modes++
+		}
+	}
+	if modes > 1 {
+		return p, errors.New("only one of -sample, -sp and -rate can be used")
+	}
+	return p, nil
+}
+
+func (p sampleParams) enabled() bool {
+	return p.every > 0 || p.probability > 0 || p.rate > 0
+}
+
+// newSampleStage creates stage of sampling, params must be resolved
+func newSampleStage(ui cli.Ui, params sampleParams) *sampleStage {
+	now := time.Now
+	return &sampleStage{
+		ui:          ui,
+		params:      params,
+		now:         now,
+		random:      rand.New(rand.NewSource(now().UnixNano())).Float64,
+		states:      map[string]*sampleState{},
+		lastSummary: now(),
+	}
+}
+
+// sampleStage passes only sample of matched rows
+type sampleStage struct {
+	ui     cli.Ui
+	params sampleParams
+	now    func() time.Time
+	random func() float64
+	// states are counters and token buckets by keys of rows
+	states  map[string]*sampleState
+	skipped int
+	// expiredSkipped is count of skipped rows of expired keys, it is shown in summary as other keys
+	expiredSkipped int
+	lastSummary    time.Time
+}
+
+type sampleState struct {
+	count      int
+	tokens     float64
+	lastRefill time.Time
+	lastSeen   time.Time
+	skipped    int
+}
+
+var _ rowStage = (*sampleStage)(nil)
+
+func (f *sampleStage) pass(row core.Row) bool {
+	key := f.rowKey(row)
+	state, ok := f.states[key]
+	if !ok {
+		state = &sampleState{tokens: f.burst(), lastRefill: f.now()}
+		f.states[key] = state
+	}
+	state.lastSeen = f.now()
+	if f.sample(state) {
+		return true
+	}
+	state.skipped++
+	f.skipped++
+	return false
+}
+
+func (f *sampleStage) sample(state *sampleState) bool {
+	switch {
+	case f.params.every > 0:
+		state.count++
+		return (state.count-1)%f.params.every == 0
+	case f.params.probability > 0:
+		return f.random() < f.params.probability
+	case f.params.rate > 0:
+		now := f.now()
+		state.tokens += now.Sub(state.lastRefill).Seconds() * f.params.rate
+		state.lastRefill = now
+		if burst := f.burst(); state.tokens > burst {
+			state.tokens = burst
+		}
+		if state.tokens < 1 {
+			return false
+		}
+		state.tokens--
+		return true
+	}
+	return true
+}
+
+// burst is capacity of token bucket, at least one row must pass for rate less than 1
+func (f *sampleStage) burst() float64 {
+	return math.Max(f.params.rate, 1)
+}
+
+// rowKey returns key like 'module=api', it is empty string if key fields are not set
+func (f *sampleStage) rowKey(row core.Row) string {
+	if len(f.params.fields) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(f.params.fields))
+	for _, field := range f.params.fields {
+		parts = append(parts, field+"="+fmt.Sprint(row.Data[field]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// flush prints summary of skipped rows, if interval is passed or rows are ended
+func (f *sampleStage) flush(final bool) {
+	f.expireStates()
+	if !final && (f.params.interval == 0 || f.now().Sub(f.lastSummary) < f.params.interval) {
+		return
+	}
+	f.lastSummary = f.now()
+	if f.skipped == 0 {
+		return
+	}
+	summary := fmt.Sprintf(sampleSummary, f.skipped)
+	if len(f.params.fields) > 0 {
+		keys := make([]string, 0, len(f.states))
+		for key, state := range f.states {
+			if state.skipped > 0 {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		parts := make([]string, 0, len(keys))
+		for _, key := range keys {
+			parts = append(parts, fmt.Sprintf("%s: %d", key, f.states[key].skipped))
+		}
+		if f.expiredSkipped > 0 {
+			parts = append(parts, fmt.Sprintf("other keys: %d", f.expiredSkipped))
+		}
+		summary += " (" + strings.Join(parts, "; ") + ")"
+	}
+	f.ui.Output(summary)
+	f.skipped = 0
+	f.expiredSkipped = 0
+	for _, state := range f.states {
+		state.skipped = 0
+	}
+}
+
+// expireStates removes states of keys without rows for long time, so states don't grow with count of keys
+func (f *sampleStage) expireStates() {
+	now := f.now()
+	for key, state := range f.states {
+		if now.Sub(state.lastSeen) >= sampleStateExpiration {
+			f.expiredSkipped += state.skipped
+			delete(f.states, key)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package command
+
+import (
+	"bytes"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/assert"
+	"github.com/voronelf/logview/core"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func newSampleStageForTest(params sampleParams, now *time.Time) (*sampleStage, *cli.MockUi) {
+	ui := &cli.MockUi{OutputWriter: &bytes.Buffer{}, ErrorWriter: &bytes.Buffer{}}
+	params, _ = params.resolve()
+	stage := newSampleStage(ui, params)
+	stage.now = func() time.Time {
+		return *now
+	}
+	stage.lastSummary = *now
+	return stage, ui
+}
+
+func newSampleRow(module string) core.Row {
+	return core.Row{Data: map[string]interface{}{"module": module}}
+}
+
+func TestSampleStage_Pass_Every(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	stage, ui := newSampleStageForTest(sampleParams{every: 3}, &now)
+
+	actual := ""
+	for i := 0; i < 7; i++ {
+		actual += strconv.FormatBool(stage.pass(newSampleRow("api"))) + " "
+	}
+	assert.Equal(t, "true false false true false false true ", actual)
+
+	stage.flush(true)
+	assert.Equal(t, "Sampling skipped 4 rows\n", ui.OutputWriter.String())
+}
+
+func TestSampleStage_Pass_EveryByKey(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	stage, ui := newSampleStageForTest(sampleParams{every: 2, fieldsList: "module"}, &now)
+
+	assert.True(t, stage.pass(newSampleRow("api")))
+	assert.True(t, stage.pass(newSampleRow("db")))
+	assert.False(t, stage.pass(newSampleRow("api")))
+	assert.False(t, stage.pass(newSampleRow("db")))
+	assert.True(t, stage.pass(newSampleRow("api")))
+	assert.False(t, stage.pass(newSampleRow("api")))
+
+	stage.flush(true)
+	assert.Equal(t, "Sampling skipped 3 rows (module=api: 2; module=db: 1)\n", ui.OutputWriter.String())
+}
+
+func TestSampleStage_Pass_Probability(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	stage, _ := newSampleStageForTest(sampleParams{probability: 0.5}, &now)
+	randoms := []float64{0.1, 0.7, 0.5, 0.49}
+	stage.random = func() float64 {
+		value := randoms[0]
+		randoms = randoms[1:]
+		return value
+	}
+
+	assert.True(t, stage.pass(newSampleRow("api")))
+	assert.False(t, stage.pass(newSampleRow("api")))
+	assert.False(t, stage.pass(newSampleRow("api")))
+	assert.True(t, stage.pass(newSampleRow("api")))
+}
+
+func TestSampleStage_Pass_Rate(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	stage, _ := newSampleStageForTest(sampleParams{rate: 2}, &now)
+
+	assert.True(t, stage.pass(newSampleRow("api")))
+	assert.True(t, stage.pass(newSampleRow("api")))
+	assert.False(t, stage.pass(newSampleRow("api")))
+	now = now.Add(500 * time.Millisecond)
+	assert.True(t, stage.pass(newSampleRow("api")))
+	assert.False(t, stage.pass(newSampleRow("api")))
+	// bucket is not filled more than rate
+	now = now.Add(10 * time.Second)
+	assert.True(t, stage.pass(newSampleRow("api")))
+	assert.True(t, stage.pass(newSampleRow("api")))
+	assert.False(t, stage.pass(newSampleRow("api")))
+}
+
+func TestSampleStage_Pass_RateLessThanOne(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	stage, _ := newSampleStageForTest(sampleParams{rate: 0.5}, &now)
+
+	assert.True(t, stage.pass(newSampleRow("api")))
+	now = now.Add(time.Second)
+	assert.False(t, stage.pass(newSampleRow("api")))
+	now = now.Add(time.Second)
+	assert.True(t, stage.pass(newSampleRow("api")))
+	now = now.Add(10 * time.Second)
+	assert.True(t, stage.pass(newSampleRow("api")))
+	assert.False(t, stage.pass(newSampleRow("api")))
+}
+
+func TestSampleStage_Flush_ExpireStates(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	stage, ui := newSampleStageForTest(sampleParams{every: 2, fieldsList: "module"}, &now)
+
+	stage.pass(newSampleRow("api"))
+	stage.pass(newSampleRow("api"))
+	now = now.Add(sampleStateExpiration / 2)
+	stage.pass(newSampleRow("db"))
+	stage.pass(newSampleRow("db"))
+	now = now.Add(sampleStateExpiration / 2)
+	stage.flush(false)
+	assert.Len(t, stage.states, 1)
+
+	// counter of expired key starts again
+	assert.True(t, stage.pass(newSampleRow("api")))
+	stage.flush(true)
+	assert.Equal(t, "Sampling skipped 2 rows (module=db: 1; other keys: 1)\n", ui.OutputWriter.String())
+}
+
+func TestSampleStage_Flush(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	stage, ui := newSampleStageForTest(sampleParams{every: 2, interval: time.Minute}, &now)
+
+	stage.pass(newSampleRow("api"))
+	stage.pass(newSampleRow("api"))
+	stage.flush(false)
+	assert.Equal(t, "", ui.OutputWriter.String())
+
+	now = now.Add(time.Minute)
+	stage.flush(false)
+	assert.Equal(t, "Sampling skipped 1 rows\n", ui.OutputWriter.String())
+
+	now = now.Add(time.Minute)
+	stage.flush(false)
+	stage.flush(true)
+	assert.Equal(t, "Sampling skipped 1 rows\n", ui.OutputWriter.String())
+}
+
+func TestSampleParams_Resolve(t *testing.T) {
+	cases := []struct {
+		params  sampleParams
+		enabled bool
+		err     bool
+	}{
+		{sampleParams{}, false, false},
+		{sampleParams{every: 10}, true, false},
+		{sampleParams{probability: 0.1, fieldsList: "module"}, true, false},
+		{sampleParams{rate: 5}, true, false},
+		{sampleParams{every: 10, rate: 5}, true, true},
+		{sampleParams{probability: 1.5}, true, true},
+		{sampleParams{every: -1}, false, true},
+	}
+	for i, cs := range cases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			actual, err := cs.params.resolve()
+			assert.Equal(t, cs.enabled, actual.enabled())
+			if cs.err {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
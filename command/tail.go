@@ -2,9 +2,12 @@ package command
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"github.com/mitchellh/cli"
 	"github.com/voronelf/logview/core"
+	"github.com/voronelf/logview/merge"
+	"path/filepath"
 	"strings"
 	"time"
 )
@@ -22,10 +25,25 @@ var _ cli.Command = (*Tail)(nil)
 func (c *Tail) Run(args []string) int {
 	var filePath, filterCondition string
 	var bytesCount int64
+	mergeParams := merge.DefaultParams()
+	readParams := core.DefaultReadParams()
+	filterParams := core.DefaultFilterParams()
+	var timezone, levelFields, fieldsOrder string
+	var prParams printParams
 	cmdFlags := flag.NewFlagSet("tail", flag.ContinueOnError)
 	cmdFlags.StringVar(&filePath, "f", "", "")
 	cmdFlags.StringVar(&filterCondition, "c", "", "")
+	cmdFlags.BoolVar(&filterParams.SearchFieldNames, "sn", filterParams.SearchFieldNames, "")
+	cmdFlags.BoolVar(&filterParams.CaseSensitive, "cs", filterParams.CaseSensitive, "")
+	cmdFlags.StringVar(&timezone, "tz", "", "")
+	cmdFlags.StringVar(&levelFields, "lf", "", "")
+	prParams.addFlags(cmdFlags)
 	cmdFlags.Int64Var(&bytesCount, "b", 0, "")
+	cmdFlags.StringVar(&readParams.Encoding, "e", readParams.Encoding, "")
+	cmdFlags.StringVar(&mergeParams.TimeField, "tf", mergeParams.TimeField, "")
+	cmdFlags.StringVar(&mergeParams.TimeLayout, "tl", mergeParams.TimeLayout, "")
+	cmdFlags.DurationVar(&mergeParams.Window, "w", mergeParams.Window, "")
+	cmdFlags.StringVar(&fieldsOrder, "order", "", "")
 	err := cmdFlags.Parse(args)
 	if err != nil {
 		return cli.RunResultHelp
@@ -33,64 +51,114 @@ func (c *Tail) Run(args []string) int {
 	if filePath == "" {
 		return cli.RunResultHelp
 	}
-
-	filter, err := c.FilterFactory.NewFilter(filterCondition)
+	filterParams.TimeField = mergeParams.TimeField
+	filterParams.Location, err = parseTimezone(timezone)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	filterParams.LevelFields = splitFields(levelFields)
+	formatParams := core.DefaultFormatParams()
+	formatParams.LevelFields = filterParams.LevelFields
+	formatParams.OriginalOrder, err = parseFieldsOrder(fieldsOrder)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	prParams, err = prParams.resolve()
 	if err != nil {
 		c.Ui.Error(err.Error())
 		return 1
 	}
+
+	filter, err := c.FilterFactory.NewFilter(filterCondition, filterParams)
+	if err != nil {
+		c.Ui.Error(formatFilterError(err))
+		return 1
+	}
 	ctx, cancelCtx := context.WithCancel(context.Background())
 	defer cancelCtx()
-	filePath = strings.Replace(filePath, "@today@", time.Now().UTC().Format("2006-01-02"), -1)
-	rowsChan, err := c.RowProvider.ReadFileTail(ctx, filePath, bytesCount)
+	rowsChan, err := readFilesTail(ctx, c.RowProvider, filePath, bytesCount, readParams, mergeParams)
 	if err != nil {
 		c.Ui.Error(err.Error())
 		return 1
 	}
-	formatParams := core.DefaultFormatParams()
-	for {
-		select {
-		case row, ok := <-rowsChan:
-			if !ok {
-				return 0
-			}
-			if row.Err != nil {
-				c.Ui.Error(row.Err.Error())
-				continue
-			}
-			if filter.Match(row) {
-				c.Ui.Output(c.Formatter.Format(row, formatParams))
-			}
-		case <-c.ShutdownCh:
-			return 0
+	printer, flushers := newPrinter(c.Ui, c.Formatter, filter, formatParams, prParams)
+	return printRows(rowsChan, c.ShutdownCh, printer, flushers)
+}
+
+// readFilesTail reads last bytes of files, rows of several files are merged in chronological order
+func readFilesTail(ctx context.Context, rowProvider core.RowProvider, filePath string, bytesCount int64, readParams core.ReadParams, mergeParams merge.Params) (<-chan core.Row, error) {
+	filePaths, err := resolveFilePaths(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if len(filePaths) == 1 {
+		return rowProvider.ReadFileTail(ctx, filePaths[0], bytesCount, readParams)
+	}
+	sources := make(map[string]<-chan core.Row, len(filePaths))
+	for _, path := range filePaths {
+		sources[path], err = rowProvider.ReadFileTail(ctx, path, bytesCount, readParams)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return merge.ByTime(ctx, sources, mergeParams), nil
+}
+
+// resolveFilePaths splits comma-separated list of files and expands glob patterns
+func resolveFilePaths(filePath string) ([]string, error) {
+	result := []string{}
+	for _, path := range strings.Split(filePath, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		path = strings.Replace(path, "@today@", time.Now().UTC().Format("2006-01-02"), -1)
+		if !strings.ContainsAny(path, "*?[") {
+			result = append(result, path)
+			continue
 		}
+		matches, err := filepath.Glob(path)
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			return nil, errors.New("no files matched by pattern: " + path)
+		}
+		result = append(result, matches...)
+	}
+	if len(result) == 0 {
+		return nil, errors.New("file path is empty")
 	}
+	return result, nil
 }
 
 func (*Tail) Synopsis() string {
-	return "Analyze last n rows from log file and show rows matched by filter condition. Args: -f filePath [-c condition] [-b bytes]"
+	return "Analyze last n rows from log files and show rows matched by filter condition. Args: -f filePath [-b bytes] [-c condition] [options]"
 }
 
 func (*Tail) Help() string {
 	text := `
-Usage: logview tail -f filePath [-b bytes] [-c condition]
+Usage: logview tail -f filePath [-b bytes] [-c condition] [options]
 
-    Analyze last b bytes from log file and show rows matched by filter condition
+    Analyze last b bytes from log file and show rows matched by filter condition.
+    If several files are given, their rows are merged in chronological order
+    and every row is labelled with its file name.
 
 Options:
 
     -f filePath    Log file path, required. Substring '@today@' will be replace
-                   to today date in format 2017-09-28.
-    -b bytes       Count of bytes to last rows in file for analyzing
-    -c condition   Filter condition. Contains one or more field checks.
-                   Every field check is 'fieldName : fieldValue', where
-                     fieldName  - name of field; can be wildcard with '*'
-                     fieldValue - value of field, case insensitive;
-                                  can be many values divided '|';
-                                  every value can be wildcard with '*';
-                                  every value can be negative, starts from '!'
-                   Field checks are divided by logic operations: 'and', 'or'.
-                   Also you can use brackets for prioritize operations.
-`
+                   to today date in format 2017-09-28. Can be a glob pattern
+                   or comma-separated list of paths.
+    -b bytes       Count of bytes to last rows in every file for analyzing
+    -e encoding    Encoding of files: auto, utf-8, utf-16le, utf-16be. Default is 'auto',
+                   it is detected by byte order mark. Invalid UTF-8 sequences are replaced.
+    -tf timeField  Name of time field for merging several files and for '_time' in condition,
+                   'time' by default
+    -tl timeLayout Layout of time field in Go format, RFC3339 by default.
+                   Numeric values are treated as unix timestamps.
+    -w window      Reorder window for rows written out of order, '1s' by default
+` + conditionHelp + filterParamsHelp + contextHelp + traceHelp + dedupHelp + sampleHelp + orderHelp
 	return strings.TrimSpace(text)
 }
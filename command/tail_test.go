@@ -33,8 +33,8 @@ func TestTail_Run(t *testing.T) {
 	channel <- row
 	close(channel)
 	mockFilter := &core.MockFilter{}
-	mockFilterFactory.On("NewFilter", "someFilter").Return(mockFilter, nil).Once()
-	mockProvider.On("ReadFileTail", mock.Anything, "someFile", int64(123)).Return((<-chan core.Row)(channel), nil).Once()
+	mockFilterFactory.On("NewFilter", "someFilter", core.DefaultFilterParams()).Return(mockFilter, nil).Once()
+	mockProvider.On("ReadFileTail", mock.Anything, "someFile", int64(123), core.DefaultReadParams()).Return((<-chan core.Row)(channel), nil).Once()
 	mockFilter.On("Match", row).Return(true).Twice()
 	mockFormatter.On("Format", row, core.DefaultFormatParams()).Return("SomeData").Twice()
 
@@ -58,10 +58,81 @@ func TestTail_Run_WithDateInFilePath(t *testing.T) {
 
 	channel := make(chan core.Row, 2)
 	close(channel)
-	mockFilterFactory.On("NewFilter", "someFilter").Return(&core.MockFilter{}, nil).Once()
-	mockProvider.On("ReadFileTail", mock.Anything, expectedFile, int64(123)).Return((<-chan core.Row)(channel), nil).Once()
+	mockFilterFactory.On("NewFilter", "someFilter", core.DefaultFilterParams()).Return(&core.MockFilter{}, nil).Once()
+	mockProvider.On("ReadFileTail", mock.Anything, expectedFile, int64(123), core.DefaultReadParams()).Return((<-chan core.Row)(channel), nil).Once()
 
 	cmd.Run([]string{"-f", incomingFile, "-b", "123", "-c", "someFilter"})
 
 	mockProvider.AssertExpectations(t)
 }
+
+func TestTail_Run_MergeFiles(t *testing.T) {
+	cmd, shutdownCh := newTailForTest()
+	defer close(shutdownCh)
+	mockFilterFactory := cmd.FilterFactory.(*core.MockFilterFactory)
+	mockProvider := cmd.RowProvider.(*core.MockRowProvider)
+	mockFormatter := cmd.Formatter.(*core.MockFormatter)
+
+	apiRow := core.Row{Data: map[string]interface{}{"ts": "2017-09-28 10:00:02"}}
+	workerRow := core.Row{Data: map[string]interface{}{"ts": "2017-09-28 10:00:01"}}
+	apiCh := make(chan core.Row, 1)
+	apiCh <- apiRow
+	close(apiCh)
+	workerCh := make(chan core.Row, 1)
+	workerCh <- workerRow
+	close(workerCh)
+	mockFilter := &core.MockFilter{}
+	filterParams := core.DefaultFilterParams()
+	filterParams.TimeField = "ts"
+	mockFilterFactory.On("NewFilter", "", filterParams).Return(mockFilter, nil).Once()
+	mockProvider.On("ReadFileTail", mock.Anything, "api.log", int64(0), core.DefaultReadParams()).Return((<-chan core.Row)(apiCh), nil).Once()
+	mockProvider.On("ReadFileTail", mock.Anything, "worker.log", int64(0), core.DefaultReadParams()).Return((<-chan core.Row)(workerCh), nil).Once()
+	mockFilter.On("Match", mock.Anything).Return(true)
+	mockFormatter.On("Format", mock.Anything, core.DefaultFormatParams()).Return(func(row core.Row, _ core.FormatParams) string {
+		return row.Source
+	})
+
+	cmd.Run([]string{"-f", "api.log, worker.log", "-tf", "ts", "-tl", "2006-01-02 15:04:05"})
+
+	mockProvider.AssertExpectations(t)
+	assert.Equal(t, "worker.log\napi.log\n", cmd.Ui.(*cli.MockUi).OutputWriter.String())
+}
+
+func TestTail_Run_FieldsOrder(t *testing.T) {
+	cmd, shutdownCh := newTailForTest()
+	defer close(shutdownCh)
+	mockFilterFactory := cmd.FilterFactory.(*core.MockFilterFactory)
+	mockProvider := cmd.RowProvider.(*core.MockRowProvider)
+	mockFormatter := cmd.Formatter.(*core.MockFormatter)
+
+	row := core.Row{Data: map[string]interface{}{"b": "1", "a": "2"}, Fields: []string{"b", "a"}}
+	channel := make(chan core.Row, 1)
+	channel <- row
+	close(channel)
+	mockFilter := &core.MockFilter{}
+	mockFilterFactory.On("NewFilter", "", core.DefaultFilterParams()).Return(mockFilter, nil).Once()
+	mockProvider.On("ReadFileTail", mock.Anything, "someFile", int64(0), core.DefaultReadParams()).Return((<-chan core.Row)(channel), nil).Once()
+	mockFilter.On("Match", row).Return(true).Once()
+	formatParams := core.DefaultFormatParams()
+	formatParams.OriginalOrder = true
+	mockFormatter.On("Format", row, formatParams).Return("SomeData").Once()
+
+	assert.Equal(t, 0, cmd.Run([]string{"-f", "someFile", "-order", "original"}))
+
+	mockFormatter.AssertExpectations(t)
+	assert.Equal(t, 1, cmd.Run([]string{"-f", "someFile", "-order", "wrong"}))
+	assert.Equal(t, "unknown fields order: wrong\n", cmd.Ui.(*cli.MockUi).ErrorWriter.String())
+}
+
+func TestResolveFilePaths(t *testing.T) {
+	paths, err := resolveFilePaths("a.log,b.log, @today@.log")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a.log", "b.log", time.Now().UTC().Format("2006-01-02") + ".log"}, paths)
+
+	paths, err = resolveFilePaths("*.go")
+	assert.Nil(t, err)
+	assert.Contains(t, paths, "tail.go")
+
+	_, err = resolveFilePaths("*.notExists")
+	assert.NotNil(t, err)
+}
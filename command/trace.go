@@ -0,0 +1,251 @@
+package command
+
+import (
+	"container/list"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/mitchellh/cli"
+	"github.com/voronelf/logview/core"
+	"sort"
+)
+
+// traceHeader is printed before group of rows with same correlation id
+const traceHeader = "--- %s ---"
+
+// traceHelp describes options -trace and -tw of trace mode
+const traceHelp = `    -trace fields  Trace mode. Comma-separated list of correlation fields, e.g. 'request_id,trace_id'.
+                   Shows all rows, which have same value of any correlation field as matched row,
+                   including earlier rows within window. Rows are grouped by correlation id,
+                   group is printed under header with id, when id is not found in window,
+                   when no rows of id come for a second, or when rows are ended.
+    -tw count      Window of trace mode in rows, 1000 by default. Earlier rows are shown,
+                   if they are not older than window, and id is traced until it
+                   is not found in window.
+`
+
+// traceParams are options of trace mode
+type traceParams struct {
+	fieldsList string
+	fields     []string
+	window     int
+}
+
+func (p *traceParams) addFlags(cmdFlags *flag.FlagSet) {
+	cmdFlags.StringVar(&p.fieldsList, "trace", "", "")
+	cmdFlags.IntVar(&p.window, "tw", 1000, "")
+}
+
+// resolve parses list of correlation fields
+func (p traceParams) resolve() (traceParams, error) {
+	p.fields = splitFields(p.fieldsList)
+	if p.enabled() && p.window <= 0 {
+		return p, errors.New("window of trace mode must be positive")
+	}
+	return p, nil
+}
+
+func (p traceParams) enabled() bool {
+	return len(p.fields) > 0
+}
+
+// newTracePrinter creates printer of trace mode, params must be resolved
+func newTracePrinter(ui cli.Ui, formatter core.Formatter, matcher rowMatcher, formatParams core.FormatParams, params traceParams) *tracePrinter {
+	return &tracePrinter{
+		ui:           ui,
+		formatter:    formatter,
+		matcher:      matcher,
+		formatParams: formatParams,
+		fields:       params.fields,
+		window:       params.window,
+		traces:       map[string]*list.Element{},
+		order:        list.New(),
+	}
+}
+
+// tracePrinter prints matched rows and all rows with same correlation ids, grouped by id
+type tracePrinter struct {
+	ui           cli.Ui
+	formatter    core.Formatter
+	matcher      rowMatcher
+	formatParams core.FormatParams
+	fields       []string
+	window       int
+	index        int
+	// buffer keeps last rows with correlation ids, which are not traced yet
+	buffer []tracedRow
+	// traces are groups of traced ids, they are elements of order
+	traces map[string]*list.Element
+	// order keeps groups from the least recently found id
+	order     *list.List
+	lastTrace string
+}
+
+type tracedRow struct {
+	row     core.Row
+	index   int
+	ids     []string
+	grouped bool
+}
+
+// traceGroup keeps rows of traced id, which are not printed yet
+type traceGroup struct {
+	id string
+	// last is index of last row with id, id is traced until it is not found in window
+	last int
+	rows []tracedRow
+	// active is true, if rows were added after previous flush
+	active bool
+}
+
+var _ rowFlusher = (*tracePrinter)(nil)
+
+func (p *tracePrinter) print(row core.Row) {
+	if row.Err != nil {
+		p.ui.Error(row.Err.Error())
+		return
+	}
+	result := p.matcher.match(row)
+	if result == rowSuppressed {
+		return
+	}
+	p.index++
+	p.expire()
+	ids := p.rowIds(row)
+	if result == rowMatched {
+		for _, id := range ids {
+			if _, ok := p.traces[id]; !ok {
+				p.startTrace(id)
+			}
+			p.touch(id)
+		}
+		if len(ids) == 0 {
+			p.output("", row)
+		} else {
+			p.add(ids[0], row)
+		}
+		return
+	}
+	traced := ""
+	for _, id := range ids {
+		if _, ok := p.traces[id]; ok {
+			p.touch(id)
+			if traced == "" {
+				traced = id
+			}
+		}
+	}
+	if traced != "" {
+		p.add(traced, row)
+		return
+	}
+	if len(ids) > 0 {
+		p.buffer = append(p.buffer, tracedRow{row: row, index: p.index, ids: ids})
+		for len(p.buffer) > 0 && p.buffer[0].index <= p.index-p.window {
+			p.buffer[0] = tracedRow{}
+			p.buffer = p.buffer[1:]
+		}
+	}
+}
+
+// rowIds returns ids like 'request_id=abc' for not empty correlation fields of row
+func (p *tracePrinter) rowIds(row core.Row) []string {
+	var ids []string
+	for _, field := range p.fields {
+		value, ok := row.Data[field]
+		if !ok || value == nil {
+			continue
+		}
+		strValue := fmt.Sprint(value)
+		if strValue != "" {
+			ids = append(ids, field+"="+strValue)
+		}
+	}
+	return ids
+}
+
+// expire prints groups of ids, which are not found in window, and forgets them
+func (p *tracePrinter) expire() {
+	for front := p.order.Front(); front != nil; front = p.order.Front() {
+		group := front.Value.(*traceGroup)
+		if p.index-group.last <= p.window {
+			return
+		}
+		p.order.Remove(front)
+		delete(p.traces, group.id)
+		p.printGroup(group)
+	}
+}
+
+// startTrace creates group of id with earlier rows from buffer
+func (p *tracePrinter) startTrace(id string) {
+	group := &traceGroup{id: id}
+	for i := range p.buffer {
+		buffered := &p.buffer[i]
+		if buffered.grouped || buffered.index <= p.index-p.window {
+			continue
+		}
+		for _, rowId := range buffered.ids {
+			if rowId == id {
+				buffered.grouped = true
+				group.rows = append(group.rows, *buffered)
+				group.active = true
+				break
+			}
+		}
+	}
+	p.traces[id] = p.order.PushBack(group)
+}
+
+// touch marks id as found in current row
+func (p *tracePrinter) touch(id string) {
+	element := p.traces[id]
+	element.Value.(*traceGroup).last = p.index
+	p.order.MoveToBack(element)
+}
+
+// add adds current row to group of traced id
+func (p *tracePrinter) add(id string, row core.Row) {
+	group := p.traces[id].Value.(*traceGroup)
+	group.rows = append(group.rows, tracedRow{row: row, index: p.index})
+	group.active = true
+}
+
+// flush prints groups, which got no rows after previous flush, all groups are printed on final flush.
+// Ids stay traced, next rows of them are printed as new group.
+func (p *tracePrinter) flush(final bool) {
+	var groups []*traceGroup
+	for element := p.order.Front(); element != nil; element = element.Next() {
+		group := element.Value.(*traceGroup)
+		if len(group.rows) > 0 && (final || !group.active) {
+			groups = append(groups, group)
+		}
+		group.active = false
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].rows[0].index < groups[j].rows[0].index
+	})
+	for _, group := range groups {
+		p.printGroup(group)
+	}
+}
+
+func (p *tracePrinter) printGroup(group *traceGroup) {
+	for _, traced := range group.rows {
+		p.output(group.id, traced.row)
+	}
+	group.rows = nil
+}
+
+// output prints row with header, if row is from other group than previous printed row
+func (p *tracePrinter) output(id string, row core.Row) {
+	if id != p.lastTrace {
+		if id == "" {
+			p.ui.Output(contextSeparator)
+		} else {
+			p.ui.Output(fmt.Sprintf(traceHeader, id))
+		}
+		p.lastTrace = id
+	}
+	p.ui.Output(p.formatter.Format(row, p.formatParams))
+}
@@ -0,0 +1,151 @@
+package command
+
+import (
+	"bytes"
+	"errors"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/voronelf/logview/core"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// newTracePrinterForTest creates printer, which matches rows with field 'match' and outputs field 'n'
+func newTracePrinterForTest(window int) (*tracePrinter, *cli.MockUi) {
+	mockFilter := &core.MockFilter{}
+	mockFilter.On("Match", mock.Anything).Return(func(row core.Row) bool {
+		return row.Data["match"] == true
+	})
+	mockFormatter := &core.MockFormatter{}
+	mockFormatter.On("Format", mock.Anything, core.DefaultFormatParams()).Return(func(row core.Row, _ core.FormatParams) string {
+		return row.Data["n"].(string)
+	})
+	ui := &cli.MockUi{OutputWriter: &bytes.Buffer{}, ErrorWriter: &bytes.Buffer{}}
+	params := traceParams{fields: []string{"rid", "tid"}, window: window}
+	return newTracePrinter(ui, mockFormatter, rowMatcher{filter: mockFilter}, core.DefaultFormatParams(), params), ui
+}
+
+func TestTracePrinter_Print(t *testing.T) {
+	// every row is 'rid:tid' with optional '!' for matched row, ids can be empty
+	cases := []struct {
+		rows     string
+		window   int
+		expected string
+	}{
+		{"a: b: a:", 10, ""},
+		{"a: b: a:!", 10, "--- rid=a --- 0 2"},
+		{"a: b: a:! b: a:", 10, "--- rid=a --- 0 2 4"},
+		{"a: b: a:! b: a: b:!", 10, "--- rid=a --- 0 2 4 --- rid=b --- 1 3 5"},
+		{"a: b: a:! b: a: a: b:! b: a:", 10, "--- rid=a --- 0 2 4 5 8 --- rid=b --- 1 3 6 7"},
+		{"a: b: c: a:!", 2, "--- rid=a --- 3"},
+		{"a: b: c: a:! b: a:", 2, "--- rid=a --- 3 5"},
+		{"a: a:! b: c: d: a:", 2, "--- rid=a --- 0 1"},
+		{":x b:x :x!", 10, "--- tid=x --- 0 1 2"},
+		{"a:x b: a:! :x", 10, "--- rid=a --- 0 2"},
+		{":! a:! :!", 10, "0 2 --- rid=a --- 1"},
+		{"a:! b:! a: b: c: c: c: b:", 2, "--- rid=a --- 0 2 --- rid=b --- 1 3"},
+	}
+	for i, cs := range cases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			printer, ui := newTracePrinterForTest(cs.window)
+			printTracedRowsForTest(printer, 0, cs.rows)
+			printer.flush(true)
+			assert.Equal(t, cs.expected, traceOutputForTest(ui))
+		})
+	}
+}
+
+func TestTracePrinter_Print_InterleavedIds(t *testing.T) {
+	printer, ui := newTracePrinterForTest(10)
+	rows := []map[string]interface{}{
+		{"rid": "r1", "n": "r1 start"},
+		{"rid": "r2", "n": "r2 start"},
+		{"rid": "r1", "n": "r1 query"},
+		{"rid": "r2", "n": "r2 error", "match": true},
+		{"rid": "r1", "n": "r1 error", "match": true},
+		{"rid": "r2", "n": "r2 end"},
+		{"rid": "r1", "n": "r1 end"},
+	}
+	for _, data := range rows {
+		printer.print(core.Row{Data: data})
+	}
+	assert.Equal(t, "", ui.OutputWriter.String())
+	printer.flush(true)
+
+	expected := "--- rid=r1 ---\nr1 start\nr1 query\nr1 error\nr1 end\n--- rid=r2 ---\nr2 start\nr2 error\nr2 end\n"
+	assert.Equal(t, expected, ui.OutputWriter.String())
+}
+
+func TestTracePrinter_Print_Expire(t *testing.T) {
+	printer, ui := newTracePrinterForTest(2)
+	printTracedRowsForTest(printer, 0, "a:! b:! a: b: c:")
+	assert.Equal(t, "", traceOutputForTest(ui))
+
+	// group is printed, when id is not found in window
+	printTracedRowsForTest(printer, 5, "c:")
+	assert.Equal(t, "--- rid=a --- 0 2", traceOutputForTest(ui))
+	printTracedRowsForTest(printer, 6, "c:")
+	assert.Equal(t, "--- rid=a --- 0 2 --- rid=b --- 1 3", traceOutputForTest(ui))
+}
+
+func TestTracePrinter_Flush(t *testing.T) {
+	printer, ui := newTracePrinterForTest(10)
+	printTracedRowsForTest(printer, 0, "a:! b:!")
+	printer.flush(false)
+	assert.Equal(t, "", traceOutputForTest(ui))
+
+	// only group without new rows after previous flush is printed
+	printTracedRowsForTest(printer, 2, "b:")
+	printer.flush(false)
+	assert.Equal(t, "--- rid=a --- 0", traceOutputForTest(ui))
+
+	// id is still traced, its next rows are printed as new group
+	printTracedRowsForTest(printer, 3, "a: c:")
+	printer.flush(true)
+	assert.Equal(t, "--- rid=a --- 0 --- rid=b --- 1 2 --- rid=a --- 3", traceOutputForTest(ui))
+}
+
+// printTracedRowsForTest prints rows like 'rid:tid' with optional '!' for matched row, ids can be empty.
+// Rows are numbered from first.
+func printTracedRowsForTest(printer *tracePrinter, first int, rows string) {
+	for n, row := range strings.Split(rows, " ") {
+		data := map[string]interface{}{"n": strconv.Itoa(first + n), "match": strings.HasSuffix(row, "!")}
+		ids := strings.Split(strings.TrimSuffix(row, "!"), ":")
+		if ids[0] != "" {
+			data["rid"] = ids[0]
+		}
+		if len(ids) > 1 && ids[1] != "" {
+			data["tid"] = ids[1]
+		}
+		printer.print(core.Row{Data: data})
+	}
+}
+
+// traceOutputForTest returns printed rows in one line
+func traceOutputForTest(ui *cli.MockUi) string {
+	return strings.TrimSpace(strings.Replace(ui.OutputWriter.String(), "\n", " ", -1))
+}
+
+func TestTracePrinter_Print_Error(t *testing.T) {
+	printer, ui := newTracePrinterForTest(10)
+	printer.print(core.Row{Err: errors.New("some error")})
+
+	assert.Equal(t, "some error\n", ui.ErrorWriter.String())
+	assert.Equal(t, "", ui.OutputWriter.String())
+}
+
+func TestTraceParams_Resolve(t *testing.T) {
+	actual, err := traceParams{fieldsList: "", window: 10}.resolve()
+	assert.Nil(t, err)
+	assert.False(t, actual.enabled())
+
+	actual, err = traceParams{fieldsList: " request_id, ,trace_id ", window: 10}.resolve()
+	assert.Nil(t, err)
+	assert.True(t, actual.enabled())
+	assert.Equal(t, []string{"request_id", "trace_id"}, actual.fields)
+
+	_, err = traceParams{fieldsList: "request_id", window: 0}.resolve()
+	assert.NotNil(t, err)
+}
@@ -13,47 +13,156 @@ import (
 )
 
 type Watch struct {
-	ShutdownCh    <-chan struct{}
-	Stdin         io.Reader
-	RowProvider   core.RowProvider   `inject:"RowProvider"`
-	FilterFactory core.FilterFactory `inject:"FilterFactory"`
-	Formatter     core.Formatter     `inject:"FormatterCliColor"`
-	Ui            cli.Ui             `inject:"CliUi"`
-	Settings      core.Settings      `inject:"Settings"`
+	ShutdownCh <-chan struct{}
+	// SubscribeReload returns channel of signals for reloading of condition file, it can be nil
+	SubscribeReload func() <-chan struct{}
+	Stdin           io.Reader
+	RowProvider     core.RowProvider   `inject:"RowProvider"`
+	FilterFactory   core.FilterFactory `inject:"FilterFactory"`
+	Formatter       core.Formatter     `inject:"FormatterCliColor"`
+	Ui              cli.Ui             `inject:"CliUi"`
+	Settings        core.Settings      `inject:"Settings"`
 }
 
 var _ cli.Command = (*Watch)(nil)
 
 func (c *Watch) Run(args []string) int {
-	filePath, filterCondition, formatParams, err := c.parseArgs(args)
+	wArgs, err := c.parseArgs(args)
 	if err != nil {
 		c.Ui.Error(err.Error())
 		return 1
 	}
-	filter, err := c.FilterFactory.NewFilter(filterCondition)
+	if wArgs.conditionFile != "" {
+		wArgs.condition, err = readConditionFile(wArgs.conditionFile)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+	}
+	var filter core.Filter
+	filter, err = c.FilterFactory.NewFilter(wArgs.condition, wArgs.filterParams)
+	if err != nil {
+		c.Ui.Error(formatFilterError(err))
+		return 1
+	}
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+	var rowsChan <-chan core.Row
+	if wArgs.gelfAddress != "" {
+		network, address := parseGelfAddress(wArgs.gelfAddress)
+		c.Ui.Output(messageWatchGelf(network, address, wArgs.condition))
+		rowsChan, err = c.RowProvider.ListenGelf(ctx, network, address)
+	} else if wArgs.execCommand != "" {
+		c.Ui.Output(messageWatchCommand(wArgs.execCommand, wArgs.condition))
+		rowsChan, err = c.RowProvider.WatchCommand(ctx, wArgs.execCommand, wArgs.readParams)
+	} else if wArgs.filePath == "" {
+		c.Ui.Output(messageWatchStdin(wArgs.condition))
+		rowsChan, err = c.RowProvider.WatchOpenedStream(ctx, c.Stdin, wArgs.readParams)
+	} else {
+		filePath := strings.Replace(wArgs.filePath, "@today@", time.Now().UTC().Format("2006-01-02"), -1)
+		c.Ui.Output(messageWatchFile(filePath, wArgs.condition))
+		rowsChan, err = c.RowProvider.WatchFileChanges(ctx, filePath, wArgs.readParams)
+	}
 	if err != nil {
 		c.Ui.Error(err.Error())
 		return 1
 	}
-	if filePath == "" {
-		c.Ui.Output(messageWatchStdin(filterCondition))
-		return c.watchStdin(filter, formatParams)
+	if wArgs.conditionFile != "" || wArgs.prompt {
+		var stopReloading func()
+		filter, stopReloading = c.startReloading(wArgs, filter)
+		defer stopReloading()
+	}
+	var exitCode int
+	if wArgs.patterns {
+		printer := newPatternsPrinter(c.Ui, c.Formatter, filter, wArgs.formatParams, wArgs.patternParams)
+		exitCode = printRows(rowsChan, c.ShutdownCh, printer, []rowFlusher{printer})
 	} else {
-		filePath = strings.Replace(filePath, "@today@", time.Now().UTC().Format("2006-01-02"), -1)
-		c.Ui.Output(messageWatchFile(filePath, filterCondition))
-		return c.watchFile(filePath, filter, formatParams)
+		printer, flushers := newPrinter(c.Ui, c.Formatter, filter, wArgs.formatParams, wArgs.printParams)
+		exitCode = printRows(rowsChan, c.ShutdownCh, printer, flushers)
+	}
+	if wArgs.execCommand != "" {
+		// command is stopped by provider after cancel, rows are closed when it is finished, else it is orphaned on exit
+		cancelCtx()
+		for range rowsChan {
+		}
+	}
+	return exitCode
+}
+
+// startReloading makes filter swappable by conditions from file or prompt while rows are watched.
+// Returned function stops watching of condition file and waits for it.
+func (c *Watch) startReloading(wArgs watchArgs, filter core.Filter) (core.Filter, func()) {
+	reloader := &filterReloader{
+		ui:       c.Ui,
+		factory:  c.FilterFactory,
+		params:   wArgs.filterParams,
+		filter:   newSwappableFilter(filter),
+		interval: conditionCheckInterval,
+	}
+	stop := func() {}
+	if wArgs.conditionFile != "" {
+		var reloadCh <-chan struct{}
+		if c.SubscribeReload != nil {
+			reloadCh = c.SubscribeReload()
+		}
+		stopCh := make(chan struct{})
+		doneCh := reloader.watchFile(stopCh, reloadCh, wArgs.conditionFile, wArgs.condition)
+		stop = func() {
+			close(stopCh)
+			<-doneCh
+		}
+	}
+	if wArgs.prompt {
+		c.Ui.Output(messagePrompt())
+		go reloader.readPrompt(c.ShutdownCh, c.Stdin)
 	}
+	return reloader.filter, stop
 }
 
-func (c *Watch) parseArgs(args []string) (filePath, condition string, formatParams core.FormatParams, err error) {
-	formatParams = core.DefaultFormatParams()
-	var tplName, showFields, accentFields string
+type watchArgs struct {
+	filePath    string
+	execCommand string
+	gelfAddress string
+	condition   string
+	// conditionFile is file with condition, which is reloaded on change
+	conditionFile string
+	// prompt enables reading of new conditions from stdin
+	prompt       bool
+	filterParams core.FilterParams
+	printParams  printParams
+	// patterns enables live report of patterns instead of rows
+	patterns      bool
+	patternParams patternParams
+	readParams    core.ReadParams
+	formatParams  core.FormatParams
+}
+
+func (c *Watch) parseArgs(args []string) (wArgs watchArgs, err error) {
+	wArgs.filterParams = core.DefaultFilterParams()
+	wArgs.readParams = core.DefaultReadParams()
+	wArgs.formatParams = core.DefaultFormatParams()
+	var tplName, showFields, accentFields, fieldsOrder, timezone, levelFields string
 	cmdFlags := flag.NewFlagSet("watch", flag.ContinueOnError)
-	cmdFlags.StringVar(&filePath, "f", "", "")
-	cmdFlags.StringVar(&condition, "c", "", "")
+	cmdFlags.StringVar(&wArgs.filePath, "f", "", "")
+	cmdFlags.StringVar(&wArgs.execCommand, "exec", "", "")
+	cmdFlags.StringVar(&wArgs.gelfAddress, "gelf", "", "")
+	cmdFlags.StringVar(&wArgs.condition, "c", "", "")
+	cmdFlags.StringVar(&wArgs.conditionFile, "cf", "", "")
+	cmdFlags.BoolVar(&wArgs.prompt, "prompt", false, "")
+	cmdFlags.BoolVar(&wArgs.filterParams.SearchFieldNames, "sn", wArgs.filterParams.SearchFieldNames, "")
+	cmdFlags.BoolVar(&wArgs.filterParams.CaseSensitive, "cs", wArgs.filterParams.CaseSensitive, "")
+	cmdFlags.StringVar(&wArgs.filterParams.TimeField, "tf", wArgs.filterParams.TimeField, "")
+	cmdFlags.StringVar(&timezone, "tz", "", "")
+	cmdFlags.StringVar(&levelFields, "lf", "", "")
+	wArgs.printParams.addFlags(cmdFlags)
+	cmdFlags.BoolVar(&wArgs.patterns, "patterns", false, "")
+	cmdFlags.DurationVar(&wArgs.patternParams.interval, "pi", 10*time.Second, "")
+	wArgs.patternParams.addFlags(cmdFlags)
+	cmdFlags.StringVar(&wArgs.readParams.Encoding, "e", wArgs.readParams.Encoding, "")
 	cmdFlags.StringVar(&tplName, "t", "", "")
 	cmdFlags.StringVar(&showFields, "o", "", "")
 	cmdFlags.StringVar(&accentFields, "a", "", "")
+	cmdFlags.StringVar(&fieldsOrder, "order", "", "")
 	err = cmdFlags.Parse(args)
 	if err != nil {
 		return
@@ -69,16 +178,24 @@ func (c *Watch) parseArgs(args []string) (filePath, condition string, formatPara
 			err = errors.New("template not found")
 			return
 		}
-		if filePath == "" {
+		if wArgs.filePath == "" && wArgs.execCommand == "" && wArgs.gelfAddress == "" {
 			tplFilePath, ok := tpl["f"]
 			if ok {
-				filePath = tplFilePath
+				wArgs.filePath = tplFilePath
+			}
+			tplExecCommand, ok := tpl["exec"]
+			if ok {
+				wArgs.execCommand = tplExecCommand
+			}
+			tplGelfAddress, ok := tpl["gelf"]
+			if ok {
+				wArgs.gelfAddress = tplGelfAddress
 			}
 		}
-		if condition == "" {
+		if wArgs.condition == "" {
 			tplCondition, ok := tpl["c"]
 			if ok {
-				condition = tplCondition
+				wArgs.condition = tplCondition
 			}
 		}
 		if showFields == "" {
@@ -93,85 +210,100 @@ func (c *Watch) parseArgs(args []string) (filePath, condition string, formatPara
 				accentFields = tplAccentFields
 			}
 		}
+		if fieldsOrder == "" {
+			tplFieldsOrder, ok := tpl["order"]
+			if ok {
+				fieldsOrder = tplFieldsOrder
+			}
+		}
+	}
+	sourcesCount := 0
+	for _, source := range []string{wArgs.filePath, wArgs.execCommand, wArgs.gelfAddress} {
+		if source != "" {
+			sourcesCount++
+		}
+	}
+	if sourcesCount > 1 {
+		err = errors.New("only one of -f, -exec and -gelf can be used")
+		return
+	}
+	if wArgs.conditionFile != "" && wArgs.condition != "" {
+		err = errors.New("only one of -c and -cf can be used")
+		return
+	}
+	if wArgs.prompt && sourcesCount == 0 {
+		err = errors.New("prompt can't be used, when log is read from stdin")
+		return
 	}
 	if showFields != "" && showFields != "*" {
 		fields := strings.Split(showFields, ",")
 		for k, v := range fields {
 			fields[k] = strings.TrimSpace(v)
 		}
-		formatParams.OutputFields = fields
+		wArgs.formatParams.OutputFields = fields
 	}
 	if accentFields != "" && accentFields != "*" {
 		fields := strings.Split(accentFields, ",")
 		for k, v := range fields {
 			fields[k] = strings.TrimSpace(v)
 		}
-		formatParams.AccentFields = fields
+		wArgs.formatParams.AccentFields = fields
 	}
-	return
-}
-
-func (c *Watch) watchFile(filePath string, filter core.Filter, formatParams core.FormatParams) int {
-	ctx, cancelCtx := context.WithCancel(context.Background())
-	defer cancelCtx()
-	rowsChan, err := c.RowProvider.WatchFileChanges(ctx, filePath)
+	wArgs.filterParams.Location, err = parseTimezone(timezone)
 	if err != nil {
-		c.Ui.Error(err.Error())
-		return 1
+		return
 	}
-	for {
-		select {
-		case row, ok := <-rowsChan:
-			if !ok {
-				return 0
-			}
-			if row.Err != nil {
-				c.Ui.Error(row.Err.Error())
-				continue
-			}
-			if filter.Match(row) {
-				c.Ui.Output(c.Formatter.Format(row, formatParams))
-			}
-		case <-c.ShutdownCh:
-			return 0
-		}
+	wArgs.filterParams.LevelFields = splitFields(levelFields)
+	wArgs.formatParams.LevelFields = wArgs.filterParams.LevelFields
+	wArgs.printParams, err = wArgs.printParams.resolve()
+	if err != nil {
+		return
 	}
+	wArgs.patternParams.timeField = wArgs.filterParams.TimeField
+	wArgs.patternParams, err = wArgs.patternParams.resolve()
+	if err != nil {
+		return
+	}
+	if wArgs.patterns && wArgs.patternParams.interval == 0 {
+		err = errors.New("interval of patterns report must be positive")
+		return
+	}
+	wArgs.formatParams.OriginalOrder, err = parseFieldsOrder(fieldsOrder)
+	return
 }
 
-func (c *Watch) watchStdin(filter core.Filter, formatParams core.FormatParams) int {
-	ctx, cancelCtx := context.WithCancel(context.Background())
-	defer cancelCtx()
-	rowsChan, err := c.RowProvider.WatchOpenedStream(ctx, c.Stdin)
-	if err != nil {
-		c.Ui.Error(err.Error())
-		return 1
+// parseFieldsOrder checks value of option -order, it returns true for original order of fields
+func parseFieldsOrder(fieldsOrder string) (bool, error) {
+	switch fieldsOrder {
+	case "", "name":
+		return false, nil
+	case "original":
+		return true, nil
+	default:
+		return false, errors.New("unknown fields order: " + fieldsOrder)
 	}
-	for {
-		select {
-		case row, ok := <-rowsChan:
-			if !ok {
-				return 0
-			}
-			if row.Err != nil {
-				c.Ui.Error(row.Err.Error())
-				continue
-			}
-			if filter.Match(row) {
-				c.Ui.Output(c.Formatter.Format(row, formatParams))
-			}
-		case <-c.ShutdownCh:
-			return 0
-		}
+}
+
+const orderHelp = `    -order order   Order of fields in output: 'name' (default) sorts fields by name,
+                   'original' keeps order of fields in source row.
+`
+
+// parseGelfAddress splits address like 'tcp://:12201' to network and address, UDP is used by default
+func parseGelfAddress(gelfAddress string) (network, address string) {
+	parts := strings.SplitN(gelfAddress, "://", 2)
+	if len(parts) == 1 {
+		return "udp", parts[0]
 	}
+	return strings.ToLower(parts[0]), parts[1]
 }
 
 func (*Watch) Synopsis() string {
-	return "Default command. Subscribe on log file changes, analyze new rows and show rows matched by filter condition. Args: [-f filePath] [-c condition]  [-o outputFields] [-a accentedFields]"
+	return "Default command. Subscribe on log file changes, analyze new rows and show rows matched by filter condition. Args: [-f filePath | -exec command | -gelf address] [-c condition | -cf conditionFile] [options]"
 }
 
 func (*Watch) Help() string {
 	text := `
-Usage: logview watch [-f filePath] [-c condition] [-o outputFields] [-a accentedFields]
+Usage: logview watch [-f filePath | -exec command | -gelf address] [-c condition | -cf conditionFile] [options]
 
     Subscribe on log file changes, analyze new rows and show rows matched by filter condition
 
@@ -179,19 +311,20 @@ Options:
 
     -f filePath    Log file path, if emtpy - used stdin. Substring '@today@' will be replace
                    to today date in format 2017-09-28.
-    -c condition   Filter condition. Contains one or more field checks.
-                   Every field check is 'fieldName : fieldValue', where
-                     fieldName  - name of field; can be wildcard with '*'
-                     fieldValue - value of field, case insensitive;
-                                  can be many values divided '|';
-                                  every value can be wildcard with '*';
-                                  every value can be negative, starts from '!'
-                   Field checks are divided by logic operations: 'and', 'or'.
-                   Also you can use brackets for prioritize operations.
-    -o fields      Comma-separated list of fields for output. Will show only this fields in that order.
+    -exec command  Run command and watch its stdout and stderr instead of file.
+                   Command is restarted with growing delay when it exits.
+    -gelf address  Listen for Graylog GELF messages instead of file. Address is
+                   like 'udp://:12201' or 'tcp://127.0.0.1:12201', UDP by default.
+    -e encoding    Encoding of input: auto, utf-8, utf-16le, utf-16be. Default is 'auto',
+                   it is detected by byte order mark. Invalid UTF-8 sequences are replaced.
+` + conditionHelp + reloadHelp + filterParamsHelp + `    -tf timeField  Name of time field for '_time' in condition, 'time' by default.
+` + contextHelp + traceHelp + dedupHelp + sampleHelp + `    -patterns      Show live report of patterns of messages instead of rows, see 'logview patterns'.
+                   Options of rows printing are ignored.
+    -pi interval   Interval of patterns report, '10s' by default.
+` + patternsHelp + `    -o fields      Comma-separated list of fields for output. Will show only this fields in that order.
                    Every field can be wildcard or negative wildcard (starts from !).
     -a fields      Comma-separated list of fields, which will show with high color.
-`
+` + orderHelp
 	return strings.TrimSpace(text)
 }
 
@@ -199,6 +332,14 @@ func messageWatchStdin(filterCondition string) string {
 	return fmt.Sprintf("Watch with filter \"%s\"\n\n", filterCondition)
 }
 
+func messageWatchCommand(command, filterCondition string) string {
+	return fmt.Sprintf("Watch command \"%s\" with filter \"%s\"\n\n", command, filterCondition)
+}
+
+func messageWatchGelf(network, address, filterCondition string) string {
+	return fmt.Sprintf("Listen GELF on %s \"%s\" with filter \"%s\"\n\n", network, address, filterCondition)
+}
+
 func messageWatchFile(filePath, filterCondition string) string {
 	return fmt.Sprintf("Watch file \"%s\" with filter \"%s\"\n\n", filePath, filterCondition)
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/voronelf/logview/core"
+	"io"
 	"sync"
 	"testing"
 	"time"
@@ -39,8 +40,8 @@ func TestWatch_Run_File(t *testing.T) {
 	formatParams.OutputFields = []string{"field1", "field2", "field3"}
 	formatParams.AccentFields = []string{"field1", "field3"}
 	mockFilter := &core.MockFilter{}
-	mockFilterFactory.On("NewFilter", "someFilter").Return(mockFilter, nil).Once()
-	mockProvider.On("WatchFileChanges", mock.Anything, "someFile").Return((<-chan core.Row)(rowsChan), nil).Once()
+	mockFilterFactory.On("NewFilter", "someFilter", core.DefaultFilterParams()).Return(mockFilter, nil).Once()
+	mockProvider.On("WatchFileChanges", mock.Anything, "someFile", core.DefaultReadParams()).Return((<-chan core.Row)(rowsChan), nil).Once()
 	mockFilter.On("Match", row).Return(true).Twice()
 	mockFormatter.On("Format", row, formatParams).Return("SomeData").Twice()
 
@@ -73,8 +74,8 @@ func TestWatch_Run_Stdin(t *testing.T) {
 	formatParams.OutputFields = []string{"field1", "field2", "field3"}
 	formatParams.AccentFields = []string{"field1", "field3"}
 	mockFilter := &core.MockFilter{}
-	mockFilterFactory.On("NewFilter", "someFilter").Return(mockFilter, nil).Once()
-	mockProvider.On("WatchOpenedStream", mock.Anything, cmd.Stdin).Return((<-chan core.Row)(rowsCh), nil).Once()
+	mockFilterFactory.On("NewFilter", "someFilter", core.DefaultFilterParams()).Return(mockFilter, nil).Once()
+	mockProvider.On("WatchOpenedStream", mock.Anything, cmd.Stdin, core.DefaultReadParams()).Return((<-chan core.Row)(rowsCh), nil).Once()
 	mockFilter.On("Match", row).Return(true).Twice()
 	mockFormatter.On("Format", row, formatParams).Return("SomeData").Twice()
 
@@ -96,7 +97,7 @@ func TestWatch_Run_Stdin(t *testing.T) {
 func TestWatch_Run_Shutdown(t *testing.T) {
 	cmd, shutdownCh := newWatchForTest()
 
-	cmd.FilterFactory.(*core.MockFilterFactory).On("NewFilter", mock.Anything).Return(&core.MockFilter{}, nil)
+	cmd.FilterFactory.(*core.MockFilterFactory).On("NewFilter", mock.Anything, mock.Anything).Return(&core.MockFilter{}, nil)
 	cmd.RowProvider.(*core.MockRowProvider).On("WatchFileChanges", mock.Anything, mock.Anything, mock.Anything).Return(make(<-chan core.Row), nil)
 
 	done := false
@@ -122,8 +123,8 @@ func TestWatch_Run_SubscribeError(t *testing.T) {
 	mockProvider := cmd.RowProvider.(*core.MockRowProvider)
 	mockFilterFactory := cmd.FilterFactory.(*core.MockFilterFactory)
 
-	mockFilterFactory.On("NewFilter", "someFilter").Return(&core.MockFilter{}, nil).Once()
-	mockProvider.On("WatchFileChanges", mock.Anything, "someFile").Return(nil, errors.New("Some error")).Once()
+	mockFilterFactory.On("NewFilter", "someFilter", core.DefaultFilterParams()).Return(&core.MockFilter{}, nil).Once()
+	mockProvider.On("WatchFileChanges", mock.Anything, "someFile", core.DefaultReadParams()).Return(nil, errors.New("Some error")).Once()
 
 	cmd.Run([]string{"-f", "someFile", "-c", "someFilter"})
 
@@ -136,7 +137,7 @@ func TestWatch_Run_FilterFactoryError(t *testing.T) {
 	cmd, shutdownCh := newWatchForTest()
 	defer close(shutdownCh)
 	mockFilterFactory := cmd.FilterFactory.(*core.MockFilterFactory)
-	mockFilterFactory.On("NewFilter", "someFilter").Return(nil, errors.New("Some error")).Once()
+	mockFilterFactory.On("NewFilter", "someFilter", core.DefaultFilterParams()).Return(nil, errors.New("Some error")).Once()
 
 	cmd.Run([]string{"-f", "someFile", "-c", "someFilter"})
 
@@ -162,8 +163,8 @@ func TestWatch_Run_Template(t *testing.T) {
 	formatParams.OutputFields = []string{"field1", "field2", "field3"}
 	formatParams.AccentFields = []string{"field1", "field3"}
 	mockSettings.On("GetTemplates").Return(templates, nil)
-	mockFilterFactory.On("NewFilter", "someFilter").Return(mockFilter, nil).Once()
-	mockProvider.On("WatchFileChanges", mock.Anything, "someFile").Return((<-chan core.Row)(rowsChan), nil).Once()
+	mockFilterFactory.On("NewFilter", "someFilter", core.DefaultFilterParams()).Return(mockFilter, nil).Once()
+	mockProvider.On("WatchFileChanges", mock.Anything, "someFile", core.DefaultReadParams()).Return((<-chan core.Row)(rowsChan), nil).Once()
 	mockFilter.On("Match", row).Return(true).Twice()
 	mockFormatter.On("Format", row, formatParams).Return("SomeData").Twice()
 
@@ -187,26 +188,39 @@ func TestWatch_parseArgs(t *testing.T) {
 	prmsDefault := core.DefaultFormatParams()
 	tplSet_1 := map[string]core.Template{"tpl1": {"f": "tplFile", "c": "tplCond"}}
 	tplSet_2 := map[string]core.Template{"tpl1": {"f": "tplFile", "c": "tplCond", "o": "field1,field2,field3", "a": "field1,field3"}}
+	tplSet_3 := map[string]core.Template{"tpl1": {"exec": "tplCmd", "c": "tplCond"}}
+	tplSet_4 := map[string]core.Template{"tpl1": {"f": "tplFile", "order": "original"}}
 	prms_2 := core.DefaultFormatParams()
 	prms_2.OutputFields = []string{"field1", "field2", "field3"}
 	prms_2.AccentFields = []string{"field1", "field3"}
+	prms_3 := core.DefaultFormatParams()
+	prms_3.OriginalOrder = true
 	cases := []struct {
 		args   string
 		tpls   map[string]core.Template
 		file   string
+		exec   string
 		cond   string
 		params core.FormatParams
 		err    bool
 	}{
-		{"-f someFile -c someCond", map[string]core.Template{}, "someFile", "someCond", prmsDefault, false},
-		{"-f someFile -c someCond", tplSet_1, "someFile", "someCond", prmsDefault, false},
-		{"-f someFile -c someCond -t tpl1", tplSet_1, "someFile", "someCond", prmsDefault, false},
-		{"-f someFile -t tpl1", tplSet_1, "someFile", "tplCond", prmsDefault, false},
-		{"-c someCond -t tpl1", tplSet_1, "tplFile", "someCond", prmsDefault, false},
-		{"-t tpl1", tplSet_1, "tplFile", "tplCond", prmsDefault, false},
-		{"-t tpl2", tplSet_1, "", "", prmsDefault, true},
-		{"-f someFile -c someCond -o field1,field2,field3 -a field1,field3", map[string]core.Template{}, "someFile", "someCond", prms_2, false},
-		{"-t tpl1", tplSet_2, "tplFile", "tplCond", prms_2, false},
+		{"-f someFile -c someCond", map[string]core.Template{}, "someFile", "", "someCond", prmsDefault, false},
+		{"-f someFile -c someCond", tplSet_1, "someFile", "", "someCond", prmsDefault, false},
+		{"-f someFile -c someCond -t tpl1", tplSet_1, "someFile", "", "someCond", prmsDefault, false},
+		{"-f someFile -t tpl1", tplSet_1, "someFile", "", "tplCond", prmsDefault, false},
+		{"-c someCond -t tpl1", tplSet_1, "tplFile", "", "someCond", prmsDefault, false},
+		{"-t tpl1", tplSet_1, "tplFile", "", "tplCond", prmsDefault, false},
+		{"-t tpl2", tplSet_1, "", "", "", prmsDefault, true},
+		{"-f someFile -c someCond -o field1,field2,field3 -a field1,field3", map[string]core.Template{}, "someFile", "", "someCond", prms_2, false},
+		{"-t tpl1", tplSet_2, "tplFile", "", "tplCond", prms_2, false},
+		{"-exec someCmd -c someCond", map[string]core.Template{}, "", "someCmd", "someCond", prmsDefault, false},
+		{"-t tpl1", tplSet_3, "", "tplCmd", "tplCond", prmsDefault, false},
+		{"-exec someCmd -t tpl1", tplSet_1, "", "someCmd", "tplCond", prmsDefault, false},
+		{"-f someFile -exec someCmd", map[string]core.Template{}, "someFile", "someCmd", "", prmsDefault, true},
+		{"-gelf :12201 -exec someCmd", map[string]core.Template{}, "", "someCmd", "", prmsDefault, true},
+		{"-f someFile -order original", map[string]core.Template{}, "someFile", "", "", prms_3, false},
+		{"-t tpl1", tplSet_4, "tplFile", "", "", prms_3, false},
+		{"-f someFile -order wrong", map[string]core.Template{}, "someFile", "", "", prmsDefault, true},
 	}
 	for i, cs := range cases {
 		t.Run(strconv.Itoa(i), func(t *testing.T) {
@@ -217,10 +231,11 @@ func TestWatch_parseArgs(t *testing.T) {
 				retErr = errors.New("some err")
 			}
 			cmd.Settings.(*core.MockSettings).On("GetTemplates").Return(cs.tpls, retErr)
-			actualFilePath, actualCondition, params, err := cmd.parseArgs(strings.Split(cs.args, " "))
-			assert.Equal(t, cs.file, actualFilePath)
-			assert.Equal(t, cs.cond, actualCondition)
-			assert.Equal(t, cs.params, params)
+			actual, err := cmd.parseArgs(strings.Split(cs.args, " "))
+			assert.Equal(t, cs.file, actual.filePath)
+			assert.Equal(t, cs.exec, actual.execCommand)
+			assert.Equal(t, cs.cond, actual.condition)
+			assert.Equal(t, cs.params, actual.formatParams)
 			if cs.err {
 				assert.NotNil(t, err)
 			} else {
@@ -230,6 +245,127 @@ func TestWatch_parseArgs(t *testing.T) {
 	}
 }
 
+func TestWatch_ParseArgs_FilterParams(t *testing.T) {
+	cmd, shutdownCh := newWatchForTest()
+	defer close(shutdownCh)
+
+	actual, err := cmd.parseArgs([]string{"-f", "someFile"})
+	assert.Nil(t, err)
+	assert.Equal(t, core.DefaultFilterParams(), actual.filterParams)
+
+	actual, err = cmd.parseArgs([]string{"-f", "someFile", "-sn", "-cs", "-tf", "ts", "-tz", "UTC"})
+	assert.Nil(t, err)
+	assert.True(t, actual.filterParams.SearchFieldNames)
+	assert.True(t, actual.filterParams.CaseSensitive)
+	assert.Equal(t, "ts", actual.filterParams.TimeField)
+	assert.Equal(t, time.UTC, actual.filterParams.Location)
+
+	_, err = cmd.parseArgs([]string{"-f", "someFile", "-tz", "Wrong/Zone"})
+	assert.NotNil(t, err)
+
+	actual, err = cmd.parseArgs([]string{"-f", "someFile", "-lf", "sev, lvl"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"sev", "lvl"}, actual.filterParams.LevelFields)
+	assert.Equal(t, []string{"sev", "lvl"}, actual.formatParams.LevelFields)
+}
+
+func TestWatch_ParseArgs_Context(t *testing.T) {
+	cmd, shutdownCh := newWatchForTest()
+	defer close(shutdownCh)
+
+	actual, err := cmd.parseArgs([]string{"-f", "someFile"})
+	assert.Nil(t, err)
+	assert.Equal(t, contextParams{}, actual.printParams.context)
+
+	actual, err = cmd.parseArgs([]string{"-f", "someFile", "-C", "3", "-A", "1"})
+	assert.Nil(t, err)
+	assert.Equal(t, contextParams{before: 3, after: 1, context: 3}, actual.printParams.context)
+
+	_, err = cmd.parseArgs([]string{"-f", "someFile", "-C", "-3"})
+	assert.NotNil(t, err)
+}
+
+func TestWatch_ParseArgs_Patterns(t *testing.T) {
+	cmd, shutdownCh := newWatchForTest()
+	defer close(shutdownCh)
+
+	actual, err := cmd.parseArgs([]string{"-f", "someFile", "-patterns", "-pi", "1m", "-mf", "msg", "-top", "5"})
+	assert.Nil(t, err)
+	assert.True(t, actual.patterns)
+	assert.Equal(t, patternParams{messageField: "msg", top: 5, similarity: 0.4, timeField: "time", interval: time.Minute}, actual.patternParams)
+
+	_, err = cmd.parseArgs([]string{"-f", "someFile", "-patterns", "-pi", "0"})
+	assert.NotNil(t, err)
+
+	_, err = cmd.parseArgs([]string{"-f", "someFile", "-sim", "2"})
+	assert.NotNil(t, err)
+}
+
+func TestWatch_ParseArgs_Trace(t *testing.T) {
+	cmd, shutdownCh := newWatchForTest()
+	defer close(shutdownCh)
+
+	actual, err := cmd.parseArgs([]string{"-f", "someFile", "-trace", "request_id,trace_id", "-tw", "50"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"request_id", "trace_id"}, actual.printParams.trace.fields)
+	assert.Equal(t, 50, actual.printParams.trace.window)
+
+	_, err = cmd.parseArgs([]string{"-f", "someFile", "-trace", "request_id", "-C", "2"})
+	assert.NotNil(t, err)
+}
+
+func TestWatch_Run_Gelf(t *testing.T) {
+	cmd, shutdownCh := newWatchForTest()
+	defer close(shutdownCh)
+	mockProvider := cmd.RowProvider.(*core.MockRowProvider)
+	mockFilterFactory := cmd.FilterFactory.(*core.MockFilterFactory)
+
+	mockFilterFactory.On("NewFilter", "someFilter", core.DefaultFilterParams()).Return(&core.MockFilter{}, nil).Once()
+	mockProvider.On("ListenGelf", mock.Anything, "tcp", "127.0.0.1:12201").Return(make(<-chan core.Row), nil).Once()
+
+	go cmd.Run([]string{"-gelf", "tcp://127.0.0.1:12201", "-c", "someFilter"})
+	time.Sleep(time.Millisecond)
+
+	mockProvider.AssertExpectations(t)
+	assert.Equal(t, messageWatchGelf("tcp", "127.0.0.1:12201", "someFilter")+"\n", cmd.Ui.(*cli.MockUi).OutputWriter.String())
+}
+
+func TestParseGelfAddress(t *testing.T) {
+	network, address := parseGelfAddress(":12201")
+	assert.Equal(t, "udp", network)
+	assert.Equal(t, ":12201", address)
+	network, address = parseGelfAddress("TCP://localhost:12201")
+	assert.Equal(t, "tcp", network)
+	assert.Equal(t, "localhost:12201", address)
+}
+
+func TestWatch_Run_Command(t *testing.T) {
+	cmd, shutdownCh := newWatchForTest()
+	defer close(shutdownCh)
+	mockProvider := cmd.RowProvider.(*core.MockRowProvider)
+	mockFormatter := cmd.Formatter.(*core.MockFormatter)
+	mockFilterFactory := cmd.FilterFactory.(*core.MockFilterFactory)
+
+	rowsChan := make(chan core.Row, 2)
+	row := core.Row{Data: map[string]interface{}{"someKey": "someValue"}, Source: "stdout"}
+	mockFilter := &core.MockFilter{}
+	mockFilterFactory.On("NewFilter", "someFilter", core.DefaultFilterParams()).Return(mockFilter, nil).Once()
+	mockProvider.On("WatchCommand", mock.Anything, "kubectl logs -f pod", core.DefaultReadParams()).Return((<-chan core.Row)(rowsChan), nil).Once()
+	mockFilter.On("Match", row).Return(true).Once()
+	mockFormatter.On("Format", row, core.DefaultFormatParams()).Return("SomeData").Once()
+
+	go cmd.Run([]string{"-exec", "kubectl logs -f pod", "-c", "someFilter"})
+	time.Sleep(time.Millisecond)
+	rowsChan <- row
+	time.Sleep(2 * time.Millisecond)
+
+	mockProvider.AssertExpectations(t)
+	mockFilter.AssertExpectations(t)
+	mockFormatter.AssertExpectations(t)
+	expectedOutput := messageWatchCommand("kubectl logs -f pod", "someFilter") + "\nSomeData\n"
+	assert.Equal(t, expectedOutput, cmd.Ui.(*cli.MockUi).OutputWriter.String())
+}
+
 func TestWatch_Run_FileWithDate(t *testing.T) {
 	cmd, shutdownCh := newWatchForTest()
 	defer close(shutdownCh)
@@ -239,11 +375,90 @@ func TestWatch_Run_FileWithDate(t *testing.T) {
 	incomingFile := "someFile_@today@.log"
 	expectedFile := "someFile_" + time.Now().UTC().Format("2006-01-02") + ".log"
 
-	mockFilterFactory.On("NewFilter", "someFilter").Return(&core.MockFilter{}, nil).Once()
-	mockProvider.On("WatchFileChanges", mock.Anything, expectedFile).Return(make(<-chan core.Row), nil).Once()
+	mockFilterFactory.On("NewFilter", "someFilter", core.DefaultFilterParams()).Return(&core.MockFilter{}, nil).Once()
+	mockProvider.On("WatchFileChanges", mock.Anything, expectedFile, core.DefaultReadParams()).Return(make(<-chan core.Row), nil).Once()
 
 	go cmd.Run([]string{"-f", incomingFile, "-c", "someFilter"})
 	time.Sleep(time.Millisecond)
 
 	mockProvider.AssertExpectations(t)
 }
+
+func TestWatch_ParseArgs_Reload(t *testing.T) {
+	cmd, shutdownCh := newWatchForTest()
+	defer close(shutdownCh)
+
+	actual, err := cmd.parseArgs([]string{"-f", "someFile", "-cf", "condition.txt", "-prompt"})
+	assert.Nil(t, err)
+	assert.Equal(t, "condition.txt", actual.conditionFile)
+	assert.True(t, actual.prompt)
+
+	_, err = cmd.parseArgs([]string{"-f", "someFile", "-cf", "condition.txt", "-c", "someFilter"})
+	assert.NotNil(t, err)
+	_, err = cmd.parseArgs([]string{"-c", "someFilter", "-prompt"})
+	assert.NotNil(t, err)
+}
+
+func TestWatch_Run_ConditionFile(t *testing.T) {
+	path, cleanup := writeConditionFileForTest(t, "# comment\nsomeFilter\n")
+	defer cleanup()
+	cmd, shutdownCh := newWatchForTest()
+	cmd.Ui = &cli.MockUi{OutputWriter: &bytes.Buffer{}, ErrorWriter: &bytes.Buffer{}}
+	reloadCh := make(chan struct{})
+	cmd.SubscribeReload = func() <-chan struct{} { return reloadCh }
+	mockProvider := cmd.RowProvider.(*core.MockRowProvider)
+	mockFilterFactory := cmd.FilterFactory.(*core.MockFilterFactory)
+
+	mockFilterFactory.On("NewFilter", "someFilter", core.DefaultFilterParams()).Return(&core.MockFilter{}, nil).Twice()
+	mockProvider.On("WatchFileChanges", mock.Anything, "someFile", core.DefaultReadParams()).Return(make(<-chan core.Row), nil).Once()
+
+	exitCh := make(chan int)
+	go func() { exitCh <- cmd.Run([]string{"-f", "someFile", "-cf", path}) }()
+	reloadCh <- struct{}{}
+	close(shutdownCh)
+	assert.Equal(t, 0, <-exitCh)
+
+	mockFilterFactory.AssertExpectations(t)
+	expectedOutput := messageWatchFile("someFile", "someFilter") + "\n" + messageFilterChanged("someFilter", "file") + "\n"
+	assert.Equal(t, expectedOutput, cmd.Ui.(*cli.MockUi).OutputWriter.String())
+}
+
+func TestWatch_Run_Prompt(t *testing.T) {
+	cmd, shutdownCh := newWatchForTest()
+	cmd.Ui = &cli.MockUi{OutputWriter: &bytes.Buffer{}, ErrorWriter: &bytes.Buffer{}}
+	stdinReader, stdinWriter := io.Pipe()
+	defer stdinWriter.Close()
+	cmd.Stdin = stdinReader
+	mockProvider := cmd.RowProvider.(*core.MockRowProvider)
+	mockFormatter := cmd.Formatter.(*core.MockFormatter)
+	mockFilterFactory := cmd.FilterFactory.(*core.MockFilterFactory)
+
+	rowsChan := make(chan core.Row, 2)
+	row := core.Row{Data: map[string]interface{}{"someKey": "someValue"}}
+	oldFilter := &core.MockFilter{}
+	oldFilter.On("Match", row).Return(false).Once()
+	newFilter := &core.MockFilter{}
+	newFilter.On("Match", row).Return(true).Once()
+	mockFilterFactory.On("NewFilter", "oldFilter", core.DefaultFilterParams()).Return(oldFilter, nil).Once()
+	mockFilterFactory.On("NewFilter", "newFilter", core.DefaultFilterParams()).Return(newFilter, nil).Once()
+	mockProvider.On("WatchFileChanges", mock.Anything, "someFile", core.DefaultReadParams()).Return((<-chan core.Row)(rowsChan), nil).Once()
+	mockFormatter.On("Format", row, core.DefaultFormatParams()).Return("SomeData").Once()
+
+	exitCh := make(chan int)
+	go func() { exitCh <- cmd.Run([]string{"-f", "someFile", "-c", "oldFilter", "-prompt"}) }()
+	rowsChan <- row
+	time.Sleep(time.Millisecond)
+	stdinWriter.Write([]byte("newFilter\n"))
+	time.Sleep(time.Millisecond)
+	rowsChan <- row
+	time.Sleep(time.Millisecond)
+	close(shutdownCh)
+	assert.Equal(t, 0, <-exitCh)
+
+	oldFilter.AssertExpectations(t)
+	newFilter.AssertExpectations(t)
+	mockFormatter.AssertExpectations(t)
+	expectedOutput := messageWatchFile("someFile", "oldFilter") + "\n" + messagePrompt() + "\n" +
+		messageFilterChanged("newFilter", "prompt") + "\n" + "SomeData\n"
+	assert.Equal(t, expectedOutput, cmd.Ui.(*cli.MockUi).OutputWriter.String())
+}
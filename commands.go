@@ -31,8 +31,9 @@ func provideCommandsDependenciesInDI(di *core.DIContainer, ui cli.Ui) {
 
 func getCommands(di *core.DIContainer) map[string]cli.CommandFactory {
 	return map[string]cli.CommandFactory{
-		"watch":    newCmdFactory(di, &command.Watch{ShutdownCh: getShutdownCh(), Stdin: os.Stdin}),
+		"watch":    newCmdFactory(di, &command.Watch{ShutdownCh: getShutdownCh(), SubscribeReload: getReloadCh, Stdin: os.Stdin}),
 		"tail":     newCmdFactory(di, &command.Tail{ShutdownCh: getShutdownCh()}),
+		"patterns": newCmdFactory(di, &command.Patterns{ShutdownCh: getShutdownCh()}),
 		"tpl":      newCmdFactory(di, &command.Tpl{}),
 		"tpl list": newCmdFactory(di, &command.TplList{}),
 		"tpl save": newCmdFactory(di, &command.TplSave{}),
@@ -61,3 +62,19 @@ func getShutdownCh() <-chan struct{} {
 	}
 	return shutdownCh
 }
+
+// getReloadCh returns a channel that receives value for every SIGHUP, signal is caught only after first call.
+func getReloadCh() <-chan struct{} {
+	reloadCh := make(chan struct{}, 1)
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, syscall.SIGHUP)
+	go func() {
+		for range signalCh {
+			select {
+			case reloadCh <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	return reloadCh
+}
@@ -7,13 +7,13 @@ type MockFilterFactory struct {
 	mock.Mock
 }
 
-// NewFilter provides a mock function with given fields: condition
-func (_m *MockFilterFactory) NewFilter(condition string) (Filter, error) {
-	ret := _m.Called(condition)
+// NewFilter provides a mock function with given fields: condition, params
+func (_m *MockFilterFactory) NewFilter(condition string, params FilterParams) (Filter, error) {
+	ret := _m.Called(condition, params)
 
 	var r0 Filter
-	if rf, ok := ret.Get(0).(func(string) Filter); ok {
-		r0 = rf(condition)
+	if rf, ok := ret.Get(0).(func(string, FilterParams) Filter); ok {
+		r0 = rf(condition, params)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(Filter)
@@ -21,8 +21,8 @@ func (_m *MockFilterFactory) NewFilter(condition string) (Filter, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(condition)
+	if rf, ok := ret.Get(1).(func(string, FilterParams) error); ok {
+		r1 = rf(condition, params)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -9,13 +9,13 @@ type MockRowProvider struct {
 	mock.Mock
 }
 
-// ReadFileTail provides a mock function with given fields: ctx, filePath, countBytes
-func (_m *MockRowProvider) ReadFileTail(ctx context.Context, filePath string, countBytes int64) (<-chan Row, error) {
-	ret := _m.Called(ctx, filePath, countBytes)
+// ListenGelf provides a mock function with given fields: ctx, network, address
+func (_m *MockRowProvider) ListenGelf(ctx context.Context, network string, address string) (<-chan Row, error) {
+	ret := _m.Called(ctx, network, address)
 
 	var r0 <-chan Row
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64) <-chan Row); ok {
-		r0 = rf(ctx, filePath, countBytes)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) <-chan Row); ok {
+		r0 = rf(ctx, network, address)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(<-chan Row)
@@ -23,8 +23,8 @@ func (_m *MockRowProvider) ReadFileTail(ctx context.Context, filePath string, co
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
-		r1 = rf(ctx, filePath, countBytes)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, network, address)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -32,13 +32,13 @@ func (_m *MockRowProvider) ReadFileTail(ctx context.Context, filePath string, co
 	return r0, r1
 }
 
-// WatchFileChanges provides a mock function with given fields: ctx, filePath
-func (_m *MockRowProvider) WatchFileChanges(ctx context.Context, filePath string) (<-chan Row, error) {
-	ret := _m.Called(ctx, filePath)
+// ReadFileTail provides a mock function with given fields: ctx, filePath, countBytes, params
+func (_m *MockRowProvider) ReadFileTail(ctx context.Context, filePath string, countBytes int64, params ReadParams) (<-chan Row, error) {
+	ret := _m.Called(ctx, filePath, countBytes, params)
 
 	var r0 <-chan Row
-	if rf, ok := ret.Get(0).(func(context.Context, string) <-chan Row); ok {
-		r0 = rf(ctx, filePath)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, ReadParams) <-chan Row); ok {
+		r0 = rf(ctx, filePath, countBytes, params)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(<-chan Row)
@@ -46,8 +46,8 @@ func (_m *MockRowProvider) WatchFileChanges(ctx context.Context, filePath string
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, filePath)
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, ReadParams) error); ok {
+		r1 = rf(ctx, filePath, countBytes, params)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -55,13 +55,13 @@ func (_m *MockRowProvider) WatchFileChanges(ctx context.Context, filePath string
 	return r0, r1
 }
 
-// WatchOpenedStream provides a mock function with given fields: ctx, stream
-func (_m *MockRowProvider) WatchOpenedStream(ctx context.Context, stream io.Reader) (<-chan Row, error) {
-	ret := _m.Called(ctx, stream)
+// WatchCommand provides a mock function with given fields: ctx, commandLine, params
+func (_m *MockRowProvider) WatchCommand(ctx context.Context, commandLine string, params ReadParams) (<-chan Row, error) {
+	ret := _m.Called(ctx, commandLine, params)
 
 	var r0 <-chan Row
-	if rf, ok := ret.Get(0).(func(context.Context, io.Reader) <-chan Row); ok {
-		r0 = rf(ctx, stream)
+	if rf, ok := ret.Get(0).(func(context.Context, string, ReadParams) <-chan Row); ok {
+		r0 = rf(ctx, commandLine, params)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(<-chan Row)
@@ -69,8 +69,54 @@ func (_m *MockRowProvider) WatchOpenedStream(ctx context.Context, stream io.Read
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, io.Reader) error); ok {
-		r1 = rf(ctx, stream)
+	if rf, ok := ret.Get(1).(func(context.Context, string, ReadParams) error); ok {
+		r1 = rf(ctx, commandLine, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WatchFileChanges provides a mock function with given fields: ctx, filePath, params
+func (_m *MockRowProvider) WatchFileChanges(ctx context.Context, filePath string, params ReadParams) (<-chan Row, error) {
+	ret := _m.Called(ctx, filePath, params)
+
+	var r0 <-chan Row
+	if rf, ok := ret.Get(0).(func(context.Context, string, ReadParams) <-chan Row); ok {
+		r0 = rf(ctx, filePath, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan Row)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, ReadParams) error); ok {
+		r1 = rf(ctx, filePath, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WatchOpenedStream provides a mock function with given fields: ctx, stream, params
+func (_m *MockRowProvider) WatchOpenedStream(ctx context.Context, stream io.Reader, params ReadParams) (<-chan Row, error) {
+	ret := _m.Called(ctx, stream, params)
+
+	var r0 <-chan Row
+	if rf, ok := ret.Get(0).(func(context.Context, io.Reader, ReadParams) <-chan Row); ok {
+		r0 = rf(ctx, stream, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan Row)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, io.Reader, ReadParams) error); ok {
+		r1 = rf(ctx, stream, params)
 	} else {
 		r1 = ret.Error(1)
 	}
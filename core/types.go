@@ -2,12 +2,19 @@ package core
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"time"
 )
 
 type Row struct {
 	Data map[string]interface{}
-	Err  error
+	// Fields contains keys of Data in original order
+	Fields []string
+	Err    error
+	Source string
+	// Repaired is count of invalid UTF-8 sequences replaced in source line
+	Repaired int
 }
 
 type Subscription struct {
@@ -17,9 +24,22 @@ type Subscription struct {
 //go:generate mockery -name RowProvider -inpkg -case=underscore
 
 type RowProvider interface {
-	WatchFileChanges(ctx context.Context, filePath string) (<-chan Row, error)
-	WatchOpenedStream(ctx context.Context, stream io.Reader) (<-chan Row, error)
-	ReadFileTail(ctx context.Context, filePath string, countBytes int64) (<-chan Row, error)
+	WatchFileChanges(ctx context.Context, filePath string, params ReadParams) (<-chan Row, error)
+	WatchOpenedStream(ctx context.Context, stream io.Reader, params ReadParams) (<-chan Row, error)
+	ReadFileTail(ctx context.Context, filePath string, countBytes int64, params ReadParams) (<-chan Row, error)
+	WatchCommand(ctx context.Context, commandLine string, params ReadParams) (<-chan Row, error)
+	ListenGelf(ctx context.Context, network, address string) (<-chan Row, error)
+}
+
+type ReadParams struct {
+	// Encoding of text: auto, utf-8, utf-16le, utf-16be. Auto is detected by byte order mark.
+	Encoding string
+}
+
+func DefaultReadParams() ReadParams {
+	return ReadParams{
+		Encoding: "auto",
+	}
 }
 
 //go:generate mockery -name Filter -inpkg -case=underscore
@@ -31,7 +51,52 @@ type Filter interface {
 //go:generate mockery -name FilterFactory -inpkg -case=underscore
 
 type FilterFactory interface {
-	NewFilter(condition string) (Filter, error)
+	NewFilter(condition string, params FilterParams) (Filter, error)
+}
+
+// ConditionError describes error in filter condition
+type ConditionError struct {
+	Condition string
+	// Column is position of wrong token in condition, it starts from 1
+	Column  int
+	Message string
+	// Expected is hint about tokens, which are expected instead of wrong token
+	Expected string
+	// Macro is name of macro from settings, if error is in condition of macro
+	Macro string
+}
+
+func (e *ConditionError) Error() string {
+	position := fmt.Sprintf("column %d", e.Column)
+	if e.Macro != "" {
+		position += fmt.Sprintf(" of macro '%s'", e.Macro)
+	}
+	if e.Expected == "" {
+		return fmt.Sprintf("%s at %s", e.Message, position)
+	}
+	return fmt.Sprintf("%s at %s, expected %s", e.Message, position, e.Expected)
+}
+
+type FilterParams struct {
+	// SearchFieldNames enables search of text without field in names of fields besides values
+	SearchFieldNames bool
+	// CaseSensitive switches on case sensitive matching of names and values of fields
+	CaseSensitive bool
+	// TimeField is name of field with time of row, it is available in condition as '_time'
+	TimeField string
+	// Location is used for times without zone
+	Location *time.Location
+	// LevelFields are names of fields with severity level in order of priority, default fields are used if empty
+	LevelFields []string
+}
+
+func DefaultFilterParams() FilterParams {
+	return FilterParams{
+		SearchFieldNames: false,
+		CaseSensitive:    false,
+		TimeField:        "time",
+		Location:         time.Local,
+	}
 }
 
 //go:generate mockery -name Formatter -inpkg -case=underscore
@@ -43,12 +108,17 @@ type Formatter interface {
 type FormatParams struct {
 	OutputFields []string
 	AccentFields []string
+	// OriginalOrder enables output of fields in order of source row instead of sorting by name
+	OriginalOrder bool
+	// LevelFields are names of fields with severity level in order of priority, default fields are used if empty
+	LevelFields []string
 }
 
 func DefaultFormatParams() FormatParams {
 	return FormatParams{
-		OutputFields: []string{},
-		AccentFields: []string{},
+		OutputFields:  []string{},
+		AccentFields:  []string{},
+		OriginalOrder: false,
 	}
 }
 
@@ -57,6 +127,10 @@ func DefaultFormatParams() FormatParams {
 type Settings interface {
 	GetTemplates() (map[string]Template, error)
 	SaveTemplate(name string, tpl Template) error
+	// GetLists returns paths of files with lists of values by names of lists
+	GetLists() (map[string]string, error)
+	// GetMacros returns conditions by names of macros, which are used in conditions like '@name'
+	GetMacros() (map[string]string, error)
 }
 
 type Template map[string]string
@@ -0,0 +1,56 @@
+package filter
+
+import (
+	"github.com/voronelf/logview/core"
+	"net"
+	"strings"
+)
+
+// NewInCidr creates filter which matches IP addresses of field, which are in any of networks
+func NewInCidr(field Field, networks []*net.IPNet) *inCidr {
+	return &inCidr{field: field, networks: networks}
+}
+
+type inCidr struct {
+	field    Field
+	networks []*net.IPNet
+}
+
+var _ core.Filter = (*inCidr)(nil)
+
+func (c *inCidr) Match(row core.Row) bool {
+	return matchField(row.Data, c.field, c.matchRowValue)
+}
+
+func (c *inCidr) matchRowValue(rowValue interface{}) bool {
+	value, ok := rowValue.(string)
+	if !ok {
+		return false
+	}
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return false
+	}
+	for _, network := range c.networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseNetwork parses CIDR like '10.0.0.0/8' or 'fd00::/8', IP address is network with single address
+func parseNetwork(value string) (*net.IPNet, error) {
+	if !strings.Contains(value, "/") {
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return nil, &net.ParseError{Type: "IP address", Text: value}
+		}
+		if ip4 := ip.To4(); ip4 != nil {
+			return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, nil
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+	}
+	_, network, err := net.ParseCIDR(value)
+	return network, err
+}
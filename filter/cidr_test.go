@@ -0,0 +1,71 @@
+package filter
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/voronelf/logview/core"
+	"net"
+	"strconv"
+	"testing"
+)
+
+func TestInCidr_Match(t *testing.T) {
+	networks := []*net.IPNet{}
+	for _, value := range []string{"10.20.0.0/16", "192.168.1.15", "fd00::/8"} {
+		network, err := parseNetwork(value)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		networks = append(networks, network)
+	}
+	cases := []struct {
+		value    interface{}
+		expected bool
+	}{
+		{"10.20.3.4", true},
+		{"10.21.3.4", false},
+		{"192.168.1.15", true},
+		{"192.168.1.16", false},
+		{"fd12:3456::1", true},
+		{"fe80::1", false},
+		{"::ffff:10.20.0.1", true},
+		{"10.20.3.4:8080", false},
+		{"not ip", false},
+		{float64(10), false},
+		{[]interface{}{"1.1.1.1", "10.20.0.1"}, true},
+	}
+	for i, cs := range cases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			filter := NewInCidr(NewField("ip", false, false), networks)
+			assert.Equal(t, cs.expected, filter.Match(core.Row{Data: map[string]interface{}{"ip": cs.value}}))
+		})
+	}
+}
+
+func TestParseNetwork(t *testing.T) {
+	cases := []struct {
+		value    string
+		expected string
+		err      bool
+	}{
+		{"10.0.0.0/8", "10.0.0.0/8", false},
+		{"10.1.2.3/8", "10.0.0.0/8", false},
+		{"10.1.2.3", "10.1.2.3/32", false},
+		{"fd00::/8", "fd00::/8", false},
+		{"::1", "::1/128", false},
+		{"10.0.0.0/33", "", true},
+		{"10.0.0", "", true},
+		{"office", "", true},
+	}
+	for i, cs := range cases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			network, err := parseNetwork(cs.value)
+			if cs.err {
+				assert.NotNil(t, err)
+				return
+			}
+			if assert.Nil(t, err) {
+				assert.Equal(t, cs.expected, network.String())
+			}
+		})
+	}
+}
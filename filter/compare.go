@@ -0,0 +1,86 @@
+package filter
+
+import (
+	"github.com/voronelf/logview/core"
+	"strings"
+)
+
+// NewCompare creates filter which compares field value with value.
+// Values are compared as numbers if both of them are numbers, else lexically with case sensitivity of field.
+func NewCompare(field Field, operation, value string) *compare {
+	return &compare{
+		field:     field,
+		operation: operation,
+		bounds:    []compareBound{newCompareBound(value)},
+	}
+}
+
+// NewBetween creates filter which matches field value between from and to inclusive
+func NewBetween(field Field, from, to string) *compare {
+	return &compare{
+		field:     field,
+		operation: opBetween,
+		bounds:    []compareBound{newCompareBound(from), newCompareBound(to)},
+	}
+}
+
+type compare struct {
+	field     Field
+	operation string
+	bounds    []compareBound
+}
+
+type compareBound struct {
+	value    string
+	number   number
+	isNumber bool
+}
+
+func newCompareBound(value string) compareBound {
+	bound := compareBound{value: value}
+	bound.number, bound.isNumber = parseNumber(value)
+	return bound
+}
+
+var _ core.Filter = (*compare)(nil)
+
+func (c *compare) Match(row core.Row) bool {
+	return matchField(row.Data, c.field, c.matchRowValue)
+}
+
+func (c *compare) matchRowValue(rowValue interface{}) bool {
+	if c.operation == opBetween {
+		return c.compareWith(rowValue, c.bounds[0]) >= 0 && c.compareWith(rowValue, c.bounds[1]) <= 0
+	}
+	return matchComparison(c.operation, c.compareWith(rowValue, c.bounds[0]))
+}
+
+func (c *compare) compareWith(rowValue interface{}, bound compareBound) int {
+	if rowNumber, ok := toNumber(rowValue); ok && bound.isNumber {
+		return compareNumbers(rowNumber, bound.number)
+	}
+	if c.field.caseSensitive {
+		return strings.Compare(toString(rowValue), bound.value)
+	}
+	return compareFold(toString(rowValue), bound.value)
+}
+
+// matchComparison checks result of comparison like strings.Compare by operation
+func matchComparison(operation string, cmp int) bool {
+	switch operation {
+	case opEqual:
+		return cmp == 0
+	case opNotEqual:
+		return cmp != 0
+	case opGreater:
+		return cmp > 0
+	case opGreaterOrEqual:
+		return cmp >= 0
+	case opLess:
+		return cmp < 0
+	case opLessOrEqual:
+		return cmp <= 0
+	default:
+		return false
+	}
+}
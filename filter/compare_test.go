@@ -0,0 +1,50 @@
+package filter
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/voronelf/logview/core"
+	"strconv"
+	"testing"
+)
+
+func TestCompare_Match(t *testing.T) {
+	cases := []struct {
+		field    string
+		op       string
+		val      string
+		expected bool
+	}{
+		{field: "intField", op: "=", val: "123", expected: true},
+		{field: "intField", op: "=", val: "123.0", expected: true},
+		{field: "intField", op: "=", val: "12", expected: false},
+		{field: "intField", op: "!=", val: "12", expected: true},
+		{field: "intField", op: ">", val: "99", expected: true},
+		{field: "intField", op: ">", val: "123", expected: false},
+		{field: "intField", op: ">=", val: "123", expected: true},
+		{field: "intField", op: "<", val: "1000", expected: true},
+		{field: "intField", op: "<=", val: "122", expected: false},
+		{field: "floatField", op: ">", val: "56.7", expected: true},
+		{field: "floatField", op: "<", val: "-1", expected: false},
+		{field: "strField", op: "=", val: "someString", expected: true},
+		{field: "strField", op: "=", val: "some*", expected: false},
+		{field: "strField", op: ">", val: "someA", expected: true},
+		{field: "strField", op: "<", val: "a", expected: false},
+		{field: "intField", op: "<", val: "abc", expected: true},
+		{field: "wrongField", op: "!=", val: "1", expected: false},
+		{field: "*Field", op: ">", val: "100", expected: true},
+		{field: "*Field", op: ">", val: "zzz", expected: false},
+	}
+	for k, cs := range cases {
+		t.Run(strconv.Itoa(k), func(t *testing.T) {
+			f := NewCompare(NewField(cs.field, false, false), cs.op, cs.val)
+			assert.Equal(t, cs.expected, f.Match(getRow()))
+		})
+	}
+}
+
+func TestCompare_Match_BigIntegers(t *testing.T) {
+	row := core.Row{Data: map[string]interface{}{"id": "1234567890123456789"}}
+	assert.True(t, NewCompare(NewField("id", false, false), "=", "1234567890123456789").Match(row))
+	assert.False(t, NewCompare(NewField("id", false, false), "=", "1234567890123456788").Match(row))
+	assert.True(t, NewCompare(NewField("id", false, false), ">", "1234567890123456788").Match(row))
+}
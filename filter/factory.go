@@ -2,11 +2,14 @@ package filter
 
 import (
 	"fmt"
-	"github.com/pkg/errors"
 	lex "github.com/timtadh/lexmachine"
 	"github.com/timtadh/lexmachine/machines"
 	"github.com/voronelf/logview/core"
+	"github.com/voronelf/logview/severity"
+	"net"
+	"regexp"
 	"strings"
+	"unicode/utf8"
 )
 
 func NewFactory() *factory {
@@ -14,10 +17,11 @@ func NewFactory() *factory {
 }
 
 type factory struct {
+	Settings core.Settings `inject:"Settings"`
 }
 
-func (*factory) NewFilter(condition string) (core.Filter, error) {
-	cleanedCondition := strings.ToLower(strings.TrimSpace(condition))
+func (f *factory) NewFilter(condition string, params core.FilterParams) (core.Filter, error) {
+	cleanedCondition := strings.TrimSpace(condition)
 	if cleanedCondition == "" || cleanedCondition == "*" {
 		return &All{}, nil
 	}
@@ -29,35 +33,76 @@ func (*factory) NewFilter(condition string) (core.Filter, error) {
 	if err != nil {
 		return nil, err
 	}
-	node, err := createFiltersTree(scanner, false)
+	node, err := newParser(lexer, scanner, params, f.Settings).parse()
 	if err != nil {
 		return nil, err
 	}
-	return &LowerCase{Child: node}, nil
+	return node, nil
 }
 
 const (
-	opWildcard = ":"
-	opAnd      = "and"
-	opOr       = "or"
+	opWildcard       = ":"
+	opEqual          = "="
+	opNotEqual       = "!="
+	opGreater        = ">"
+	opGreaterOrEqual = ">="
+	opLess           = "<"
+	opLessOrEqual    = "<="
+	opRegex          = "~"
+	opNotRegex       = "!~"
+	opBetween        = "between"
+	opIn             = "in"
+	opAnd            = "and"
+	opOr             = "or"
+	opNot            = "not"
+	fnMissing        = "missing"
+	fnAll            = "all"
+	fnInCidr         = "in_cidr"
+	// timeFieldAlias is replaced by name of time field from params
+	timeFieldAlias = "_time"
+	// levelFieldAlias is compared with normalized level of row from any of level fields
+	levelFieldAlias = "_level"
+)
+
+// hints about expected tokens for errors
+const (
+	expectedOperand        = "field name, text, 'not' or open bracket"
+	expectedFieldOperation = "field operation"
+	expectedInValues       = "values in brackets or list like '@name'"
+)
+
+// modifiers of field operation, e.g. '=/c', switch case sensitivity for one operation
+const (
+	modifierCaseSensitive   = "/c"
+	modifierCaseInsensitive = "/i"
 )
 
 const (
 	typeString int = iota
 	typeFieldOperation
 	typeCondOperation
+	typeNotOperation
 	typeOpenBracket
 	typeCloseBracket
+	typeComma
+	typeModifier
+	typeReference
 )
 
 func initLexer() (*lex.Lexer, error) {
 	lexer := lex.NewLexer()
-	lexer.Add([]byte("([a-z]|[0-9]|_|\\-|\\.|\\*|\\!|\\|)+"), analyzeString)
+	// '!' is allowed only in the beginning of negative pattern, e.g. '!abc|!def*', else it is part of operations '!=' and '!~'
+	lexer.Add([]byte("\\!?(([a-z]|[A-Z]|[0-9]|_|\\-|\\.|\\*|\\||\\[|\\]|\\+)|\\|\\!)+"), analyzeString)
 	lexer.Add([]byte("\\'"), takeStringBetweenQuotes('\''))
 	lexer.Add([]byte("\\\""), takeStringBetweenQuotes('"'))
-	lexer.Add([]byte("\\:"), token(typeFieldOperation))
+	for _, operation := range []string{"\\:", "\\=", "\\!\\=", "\\>", "\\>\\=", "\\<", "\\<\\=", "\\~", "\\!\\~"} {
+		lexer.Add([]byte(operation), token(typeFieldOperation))
+	}
+	lexer.Add([]byte("\\/([a-z]|[A-Z])*"), token(typeModifier))
+	lexer.Add([]byte("@([a-z]|[A-Z]|[0-9]|_|\\-|\\.|\\/|\\~)+"), token(typeReference))
 	lexer.Add([]byte("\\("), token(typeOpenBracket))
 	lexer.Add([]byte("\\)"), token(typeCloseBracket))
+	lexer.Add([]byte("\\,"), token(typeComma))
 	lexer.Add([]byte("( |\t|\n|\r)+"), skip)
 
 	err := lexer.Compile()
@@ -76,8 +121,16 @@ func token(tokenType int) lex.Action {
 func analyzeString(s *lex.Scanner, m *machines.Match) (interface{}, error) {
 	tokenType := typeString
 	strMatch := string(m.Bytes)
-	if strMatch == opAnd || strMatch == opOr {
+	switch lower := strings.ToLower(strMatch); lower {
+	case opAnd, opOr:
 		tokenType = typeCondOperation
+		strMatch = lower
+	case opNot:
+		tokenType = typeNotOperation
+		strMatch = lower
+	case opBetween:
+		tokenType = typeFieldOperation
+		strMatch = lower
 	}
 	return s.Token(tokenType, strMatch, m), nil
 }
@@ -97,128 +150,556 @@ func takeStringBetweenQuotes(quoteSymbol byte) lex.Action {
 				match.EndLine += 1
 			}
 			if scan.Text[tc] == quoteSymbol {
-				match.TC = scan.TC
 				scan.TC = tc + 1
 				match.Bytes = str
 				return token(typeString)(scan, match)
 			}
 			str = append(str, scan.Text[tc])
 		}
-		return nil,
-			fmt.Errorf("unclosed string value, started at %d, (%d, %d)",
-				match.TC, match.StartLine, match.StartColumn)
+		return nil, newConditionError(scan.Text, match.TC, "Unclosed quoted string", "closing quote")
 	}
 }
 
-func createFiltersTree(s *lex.Scanner, expectCloseBracket bool) (core.Filter, error) {
-	var root core.Filter
-	var logicOperationToken *lex.Token
-	for i := 0; i < 100; i++ {
-		firstToken, err := requiredTokenFromScanner(s)
+// newConditionError creates error pointing to byte position tc of condition text
+func newConditionError(text []byte, tc int, message, expected string) *core.ConditionError {
+	if tc > len(text) {
+		tc = len(text)
+	}
+	return &core.ConditionError{
+		Condition: string(text),
+		Column:    utf8.RuneCount(text[:tc]) + 1,
+		Message:   message,
+		Expected:  expected,
+	}
+}
+
+// logicPriorities defines priorities of binary logic operations, operation with greater priority binds tighter
+var logicPriorities = map[string]int{
+	opOr:  1,
+	opAnd: 2,
+}
+
+// newParser creates parser, settings can be nil, then references to lists are paths of files and there are no macros
+func newParser(lexer *lex.Lexer, s *lex.Scanner, params core.FilterParams, settings core.Settings) *parser {
+	return &parser{lexer: lexer, scanner: s, params: params, settings: settings, levels: severity.NewNormalizer(params.LevelFields)}
+}
+
+// parser builds filters tree from tokens by precedence climbing
+type parser struct {
+	lexer    *lex.Lexer
+	scanner  *lex.Scanner
+	params   core.FilterParams
+	settings core.Settings
+	levels   *severity.Normalizer
+	peeked   *lex.Token
+	eof      bool
+	// macros are loaded from settings on first reference and shared with parsers of macros
+	macros map[string]string
+	// macroPath is chain of macros, which are parsed now, it is used for detection of cycles
+	macroPath []string
+}
+
+func (p *parser) parse() (core.Filter, error) {
+	root, err := p.parseExpression(0)
+	if err != nil {
+		return nil, err
+	}
+	tok, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if tok != nil {
+		if tok.Type == typeCloseBracket {
+			return nil, p.errorAt(tok, "Found close bracket without open bracket", "logic operation")
+		}
+		return nil, p.errorAt(tok, unexpectedToken(tok), "logic operation")
+	}
+	return root, nil
+}
+
+// parseExpression parses sequence of operands divided by logic operations with priority not less than minPriority
+func (p *parser) parseExpression(minPriority int) (core.Filter, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok, err := p.peek()
 		if err != nil {
 			return nil, err
 		}
-		var node core.Filter
-		switch firstToken.Type {
-		case typeString:
-			node, err = createFieldOperation(s, firstToken)
-			if err != nil {
-				return nil, err
-			}
+		if tok == nil || tok.Type != typeCondOperation {
+			return left, nil
+		}
+		priority := logicPriorities[tok.Value.(string)]
+		if priority < minPriority {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseExpression(priority + 1)
+		if err != nil {
+			return nil, err
+		}
+		left, err = p.createLogicOperation(tok, left, right)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseOperand parses field operation, function, text search, expression in brackets or negation of operand
+func (p *parser) parseOperand() (core.Filter, error) {
+	tok, err := p.required(expectedOperand)
+	if err != nil {
+		return nil, err
+	}
+	switch tok.Type {
+	case typeString:
+		nextToken, err := p.peek()
+		if err != nil {
+			return nil, err
+		}
+		if nextToken == nil {
+			return p.createText(tok), nil
+		}
+		switch nextToken.Type {
 		case typeOpenBracket:
-			node, err = createFiltersTree(s, true)
-			if err != nil {
-				return nil, err
-			}
+			return p.parseFunction(tok)
+		case typeFieldOperation:
+			return p.parseFieldOperation(string(tok.Lexeme), false)
 		default:
-			return nil, errors.Errorf("Unexpected token type for '%s'. Expected field name or open bracket", string(firstToken.Lexeme))
-		}
-		if logicOperationToken != nil {
-			root, err = createLogicOperation(logicOperationToken, root, node)
-			if err != nil {
-				return nil, err
+			if p.isInOperation(nextToken) {
+				return p.parseFieldOperation(string(tok.Lexeme), false)
 			}
-		} else {
-			root = node
+			return p.createText(tok), nil
+		}
+	case typeNotOperation:
+		child, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Child: child}, nil
+	case typeOpenBracket:
+		node, err := p.parseExpression(0)
+		if err != nil {
+			return nil, err
+		}
+		closeToken, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		if closeToken == nil {
+			return nil, p.errorAtEnd("Not found close bracket", "logic operation or close bracket")
+		}
+		if closeToken.Type != typeCloseBracket {
+			return nil, p.errorAt(closeToken, unexpectedToken(closeToken), "logic operation or close bracket")
+		}
+		return node, nil
+	case typeReference:
+		return p.parseMacro(tok)
+	default:
+		return nil, p.errorAt(tok, unexpectedToken(tok), expectedOperand)
+	}
+}
+
+// parseMacro parses condition of macro by reference like '@name' as expression in brackets.
+// Errors in condition of macro point into the macro.
+func (p *parser) parseMacro(refToken *lex.Token) (core.Filter, error) {
+	name := string(refToken.Lexeme[1:])
+	for i, parsed := range p.macroPath {
+		if parsed == name {
+			cycle := append(append([]string{}, p.macroPath[i:]...), name)
+			return nil, p.errorAt(refToken, "Cycle of macros: "+strings.Join(cycle, " -> "), "")
+		}
+	}
+	macros, err := p.loadMacros(refToken)
+	if err != nil {
+		return nil, err
+	}
+	condition, ok := macros[name]
+	if !ok {
+		return nil, p.errorAt(refToken, fmt.Sprintf("Unknown macro '%s'", name), "")
+	}
+	scanner, err := p.lexer.Scanner([]byte(strings.TrimSpace(condition)))
+	if err != nil {
+		return nil, err
+	}
+	macroParser := newParser(p.lexer, scanner, p.params, p.settings)
+	macroParser.macros = macros
+	macroParser.macroPath = append(append([]string{}, p.macroPath...), name)
+	node, err := macroParser.parse()
+	if err != nil {
+		// error from nested macro already points into it
+		if condErr, ok := err.(*core.ConditionError); ok && condErr.Macro == "" {
+			condErr.Macro = name
 		}
+		return nil, err
+	}
+	return node, nil
+}
 
-		token, eof, err := tokenFromScanner(s)
+// loadMacros loads macros from settings once per condition
+func (p *parser) loadMacros(refToken *lex.Token) (map[string]string, error) {
+	if p.macros != nil || p.settings == nil {
+		return p.macros, nil
+	}
+	macros, err := p.settings.GetMacros()
+	if err != nil {
+		return nil, p.errorAt(refToken, "Settings loading error: "+err.Error(), "")
+	}
+	p.macros = macros
+	return macros, nil
+}
+
+// createText creates search of text in all fields for word or quoted string without field operation
+func (p *parser) createText(textToken *lex.Token) core.Filter {
+	return NewText(string(textToken.Lexeme), p.params.SearchFieldNames, p.params.CaseSensitive)
+}
+
+// parseFunction parses call of function like 'exists(field)' or field operation like 'all(field): value'
+func (p *parser) parseFunction(nameToken *lex.Token) (core.Filter, error) {
+	p.next() // open bracket
+	args := []*lex.Token{}
+	for {
+		argToken, err := p.required("argument of function")
 		if err != nil {
 			return nil, err
 		}
-		if eof {
-			if expectCloseBracket {
-				return nil, errors.Errorf("Not found close bracket")
-			} else {
-				return root, nil
-			}
+		if argToken.Type != typeString && argToken.Type != typeReference {
+			return nil, p.errorAt(argToken, unexpectedToken(argToken), "argument of function")
+		}
+		args = append(args, argToken)
+		delimiterToken, err := p.required("comma or close bracket")
+		if err != nil {
+			return nil, err
+		}
+		if delimiterToken.Type == typeCloseBracket {
+			break
 		}
-		switch token.Type {
-		case typeCloseBracket:
-			if expectCloseBracket {
-				return root, nil
+		if delimiterToken.Type != typeComma {
+			return nil, p.errorAt(delimiterToken, unexpectedToken(delimiterToken), "comma or close bracket")
+		}
+	}
+	name := strings.ToLower(string(nameToken.Lexeme))
+	if name == fnInCidr {
+		return p.parseInCidr(nameToken, args)
+	}
+	if len(args) != 1 {
+		return nil, p.errorAt(nameToken, fmt.Sprintf("Function '%s' expects one argument, %d given", name, len(args)), "")
+	}
+	if args[0].Type != typeString {
+		return nil, p.errorAt(args[0], unexpectedToken(args[0]), "field name")
+	}
+	fieldName := string(args[0].Lexeme)
+	switch name {
+	case fnAll:
+		return p.parseFieldOperation(fieldName, true)
+	case fnMissing:
+		return &Not{Child: NewPredicate("exists", p.newField(fieldName, p.params.CaseSensitive, false))}, nil
+	}
+	predicate := NewPredicate(name, p.newField(fieldName, p.params.CaseSensitive, false))
+	if predicate == nil {
+		return nil, p.errorAt(nameToken, fmt.Sprintf("Unknown function '%s'", name), "")
+	}
+	return predicate, nil
+}
+
+// parseInCidr creates filter by arguments like 'in_cidr(field, "10.0.0.0/8", @office)'.
+// Every network is CIDR or IP address, reference is list of networks.
+func (p *parser) parseInCidr(nameToken *lex.Token, args []*lex.Token) (core.Filter, error) {
+	if len(args) < 2 {
+		return nil, p.errorAt(nameToken, fmt.Sprintf("Function '%s' expects field and networks, %d arguments given", fnInCidr, len(args)), "")
+	}
+	if args[0].Type != typeString {
+		return nil, p.errorAt(args[0], unexpectedToken(args[0]), "field name")
+	}
+	networks := []*net.IPNet{}
+	for _, arg := range args[1:] {
+		if arg.Type == typeReference {
+			values, err := p.loadList(arg)
+			if err != nil {
+				return nil, err
 			}
-			return nil, errors.Errorf("Found close bracket without open bracket")
-		case typeCondOperation:
-			if logicOperationToken != nil && string(token.Lexeme) != string(logicOperationToken.Lexeme) {
-				return nil, errors.Errorf("Different logic operations in one condition. Priorities is not defined. Let's use brackets")
+			for _, value := range values {
+				network, err := parseNetwork(value)
+				if err != nil {
+					return nil, p.errorAt(arg, fmt.Sprintf("Invalid network '%s' in list '%s'", value, string(arg.Lexeme[1:])), "CIDR or IP address")
+				}
+				networks = append(networks, network)
 			}
-			logicOperationToken = token
 			continue
-		default:
-			return nil, errors.Errorf("Unexpected token '%s'. Expected logic operation or close bracket")
 		}
+		network, err := parseNetwork(string(arg.Lexeme))
+		if err != nil {
+			return nil, p.errorAt(arg, fmt.Sprintf("Invalid network '%s'", string(arg.Lexeme)), "CIDR or IP address")
+		}
+		networks = append(networks, network)
 	}
-	return nil, errors.New("Many logical operations in condition")
+	return NewInCidr(p.newField(string(args[0].Lexeme), p.params.CaseSensitive, false), networks), nil
 }
 
-func requiredTokenFromScanner(s *lex.Scanner) (*lex.Token, error) {
-	tok, err, eof := s.Next()
+// loadList loads values of list by reference like '@name', name is name of list in settings or path of file
+func (p *parser) loadList(refToken *lex.Token) ([]string, error) {
+	path := string(refToken.Lexeme[1:])
+	if p.settings != nil {
+		lists, err := p.settings.GetLists()
+		if err != nil {
+			return nil, p.errorAt(refToken, "Settings loading error: "+err.Error(), "")
+		}
+		if listPath, ok := lists[path]; ok {
+			path = listPath
+		}
+	}
+	values, err := readList(path)
+	if err != nil {
+		return nil, p.errorAt(refToken, "List loading error: "+err.Error(), "")
+	}
+	return values, nil
+}
+
+// peek returns next token without moving forward, nil is returned on eof
+func (p *parser) peek() (*lex.Token, error) {
+	if p.peeked != nil || p.eof {
+		return p.peeked, nil
+	}
+	tok, err, eof := p.scanner.Next()
 	if err != nil {
+		if unconsumed, ok := err.(*machines.UnconsumedInput); ok {
+			symbol, _ := utf8.DecodeRune(unconsumed.Text[unconsumed.StartTC:])
+			return nil, newConditionError(unconsumed.Text, unconsumed.StartTC, fmt.Sprintf("Unexpected symbol '%c'", symbol), "")
+		}
 		return nil, err
 	}
 	if eof {
-		return nil, errors.New("Unexpected eof")
+		p.eof = true
+		return nil, nil
 	}
-	return tok.(*lex.Token), nil
+	p.peeked = tok.(*lex.Token)
+	return p.peeked, nil
+}
+
+// next returns next token, nil is returned on eof
+func (p *parser) next() (*lex.Token, error) {
+	tok, err := p.peek()
+	p.peeked = nil
+	return tok, err
 }
 
-func tokenFromScanner(s *lex.Scanner) (*lex.Token, bool, error) {
-	tok, err, eof := s.Next()
-	if err != nil || eof {
-		return nil, eof, err
+// required returns next token, eof is error with hint about expected token
+func (p *parser) required(expected string) (*lex.Token, error) {
+	tok, err := p.next()
+	if err != nil {
+		return nil, err
 	}
-	return tok.(*lex.Token), eof, nil
+	if tok == nil {
+		return nil, p.errorAtEnd("Unexpected end of condition", expected)
+	}
+	return tok, nil
+}
+
+// errorAt creates error pointing to token
+func (p *parser) errorAt(tok *lex.Token, message, expected string) error {
+	return newConditionError(p.scanner.Text, tok.TC, message, expected)
+}
+
+// errorAtEnd creates error pointing to end of condition
+func (p *parser) errorAtEnd(message, expected string) error {
+	return newConditionError(p.scanner.Text, len(p.scanner.Text), message, expected)
 }
 
-func createFieldOperation(s *lex.Scanner, fieldToken *lex.Token) (core.Filter, error) {
-	operationToken, err := requiredTokenFromScanner(s)
+func unexpectedToken(tok *lex.Token) string {
+	return fmt.Sprintf("Unexpected token '%s'", string(tok.Lexeme))
+}
+
+// parseFieldOperation parses operation and value for field, if all is true every element of array must be matched
+func (p *parser) parseFieldOperation(fieldName string, all bool) (core.Filter, error) {
+	operationToken, err := p.required(expectedFieldOperation)
 	if err != nil {
 		return nil, err
 	}
-	operation := string(operationToken.Lexeme)
-	if operationToken.Type != typeFieldOperation {
-		return nil, errors.Errorf("Unexpected operation type '%s'", operation)
+	operation, _ := operationToken.Value.(string)
+	if p.isInOperation(operationToken) {
+		operation = opIn
+	} else if operationToken.Type != typeFieldOperation {
+		return nil, p.errorAt(operationToken, unexpectedToken(operationToken), expectedFieldOperation)
 	}
-	fieldValueToken, err := requiredTokenFromScanner(s)
+	caseSensitive, err := p.parseModifier()
 	if err != nil {
 		return nil, err
 	}
-	if fieldValueToken.Type != typeString {
-		return nil, errors.Errorf("Unexpected token type for field value: '%s'", string(fieldValueToken.Lexeme))
+	field := p.newField(fieldName, caseSensitive, all)
+	if operation == opBetween {
+		return p.parseBetween(field)
+	}
+	if operation == opIn {
+		return p.parseIn(field)
+	}
+	fieldValueToken, err := p.requiredValue()
+	if err != nil {
+		return nil, err
 	}
+	value := string(fieldValueToken.Lexeme)
 	switch operation {
 	case opWildcard:
-		return NewWildcard(string(fieldToken.Lexeme), string(fieldValueToken.Lexeme)), nil
+		return NewWildcard(field, value), nil
+	case opEqual, opNotEqual, opGreater, opGreaterOrEqual, opLess, opLessOrEqual:
+		if level := severity.ParseName(value); level != severity.Unknown && p.isLevelField(fieldName) {
+			return NewLevelCompare(field, p.aliasLevels(fieldName), operation, level), nil
+		}
+		if bound, ok := ParseTimeBound(value, p.params.Location); ok {
+			return NewTimeCompare(field, operation, bound, p.params.Location), nil
+		}
+		return NewCompare(field, operation, value), nil
+	case opRegex, opNotRegex:
+		// expression is checked as it is written, so error doesn't contain added flag
+		expr, err := regexp.Compile(value)
+		if err != nil {
+			return nil, p.errorAt(fieldValueToken, "Invalid regular expression: "+err.Error(), "")
+		}
+		// case insensitive regular expression still can be switched by flag '(?-i)'
+		if !caseSensitive {
+			expr = regexp.MustCompile("(?i)" + value)
+		}
+		return NewRegex(field, expr, operation == opNotRegex), nil
+	default:
+		return nil, p.errorAt(operationToken, fmt.Sprintf("Unknown operation '%s'", operation), expectedFieldOperation)
+	}
+}
+
+// isInOperation checks that token is word 'in' without quotes. It isn't reserved by lexer,
+// so 'in' is operation only after field name and still can be used as name of field or text.
+func (p *parser) isInOperation(tok *lex.Token) bool {
+	if tok.Type != typeString || !strings.EqualFold(string(tok.Lexeme), opIn) {
+		return false
+	}
+	first := p.scanner.Text[tok.TC]
+	return first != '\'' && first != '"'
+}
+
+// parseBetween parses bounds of operation like 'field between 10 and 20', bounds can be times
+func (p *parser) parseBetween(field Field) (core.Filter, error) {
+	fromToken, err := p.requiredValue()
+	if err != nil {
+		return nil, err
+	}
+	andToken, err := p.required("'and'")
+	if err != nil {
+		return nil, err
+	}
+	if andToken.Type != typeCondOperation || andToken.Value != opAnd {
+		return nil, p.errorAt(andToken, unexpectedToken(andToken), "'and'")
+	}
+	toToken, err := p.requiredValue()
+	if err != nil {
+		return nil, err
+	}
+	from, to := string(fromToken.Lexeme), string(toToken.Lexeme)
+	fromLevel, toLevel := severity.ParseName(from), severity.ParseName(to)
+	if fromLevel != severity.Unknown && toLevel != severity.Unknown && p.isLevelField(field.name) {
+		return NewLevelBetween(field, p.aliasLevels(field.name), fromLevel, toLevel), nil
+	}
+	fromBound, fromIsTime := ParseTimeBound(from, p.params.Location)
+	toBound, toIsTime := ParseTimeBound(to, p.params.Location)
+	if fromIsTime && toIsTime {
+		return NewTimeBetween(field, fromBound, toBound, p.params.Location), nil
+	}
+	return NewBetween(field, from, to), nil
+}
+
+// parseIn parses values of operation like 'field in (a, b, c)' or list of values like 'field in @ids.txt'
+func (p *parser) parseIn(field Field) (core.Filter, error) {
+	tok, err := p.required(expectedInValues)
+	if err != nil {
+		return nil, err
+	}
+	if tok.Type == typeReference {
+		values, err := p.loadList(tok)
+		if err != nil {
+			return nil, err
+		}
+		return NewInSet(field, values), nil
+	}
+	if tok.Type != typeOpenBracket {
+		return nil, p.errorAt(tok, unexpectedToken(tok), expectedInValues)
+	}
+	values := []string{}
+	for {
+		valueToken, err := p.requiredValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, string(valueToken.Lexeme))
+		delimiterToken, err := p.required("comma or close bracket")
+		if err != nil {
+			return nil, err
+		}
+		if delimiterToken.Type == typeCloseBracket {
+			break
+		}
+		if delimiterToken.Type != typeComma {
+			return nil, p.errorAt(delimiterToken, unexpectedToken(delimiterToken), "comma or close bracket")
+		}
+	}
+	return NewInSet(field, values), nil
+}
+
+// requiredValue returns next token, which must be value of field
+func (p *parser) requiredValue() (*lex.Token, error) {
+	tok, err := p.required("field value")
+	if err != nil {
+		return nil, err
+	}
+	if tok.Type != typeString {
+		return nil, p.errorAt(tok, unexpectedToken(tok), "field value")
+	}
+	return tok, nil
+}
+
+// newField creates field, alias '_time' is replaced by time field from params
+func (p *parser) newField(name string, caseSensitive, all bool) Field {
+	if strings.EqualFold(name, timeFieldAlias) {
+		name = p.params.TimeField
+	}
+	return NewField(name, caseSensitive, all)
+}
+
+// isLevelField checks that field is compared by normalized level, it is alias '_level' or one of level fields
+func (p *parser) isLevelField(name string) bool {
+	return strings.EqualFold(name, levelFieldAlias) || p.levels.IsLevelField(name)
+}
+
+// aliasLevels returns normalizer of level fields for alias '_level', other fields are compared by own values
+func (p *parser) aliasLevels(name string) *severity.Normalizer {
+	if strings.EqualFold(name, levelFieldAlias) {
+		return p.levels
+	}
+	return nil
+}
+
+// parseModifier parses optional modifier of field operation, case sensitivity is taken from params without modifier
+func (p *parser) parseModifier() (caseSensitive bool, err error) {
+	tok, err := p.peek()
+	if err != nil {
+		return false, err
+	}
+	if tok == nil || tok.Type != typeModifier {
+		return p.params.CaseSensitive, nil
+	}
+	p.next()
+	switch strings.ToLower(string(tok.Lexeme)) {
+	case modifierCaseSensitive:
+		return true, nil
+	case modifierCaseInsensitive:
+		return false, nil
 	default:
-		return nil, errors.Errorf("Unknown operation '%s'", operation)
+		return false, p.errorAt(tok, fmt.Sprintf("Unknown modifier '%s'", string(tok.Lexeme)), "modifier '/c' or '/i'")
 	}
 }
 
-func createLogicOperation(operationToken *lex.Token, left, right core.Filter) (core.Filter, error) {
-	operation := string(operationToken.Lexeme)
+func (p *parser) createLogicOperation(operationToken *lex.Token, left, right core.Filter) (core.Filter, error) {
+	operation, _ := operationToken.Value.(string)
 	if operationToken.Type != typeCondOperation {
-		return nil, errors.Errorf("Unexpected token type for logic operation '%s'", operation)
+		return nil, p.errorAt(operationToken, unexpectedToken(operationToken), "logic operation")
 	}
 	switch operation {
 	case opAnd:
@@ -232,6 +713,6 @@ func createLogicOperation(operationToken *lex.Token, left, right core.Filter) (c
 			Right: right,
 		}, nil
 	default:
-		return nil, errors.Errorf("Unknown logic operation '%s'", operation)
+		return nil, p.errorAt(operationToken, fmt.Sprintf("Unknown logic operation '%s'", operation), "logic operation")
 	}
 }
@@ -10,6 +10,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 func getRow() core.Row {
@@ -89,11 +90,71 @@ func TestFactory_NewFilter(t *testing.T) {
 		30: {condition: "intField:'123' and (strField:wrongString or floatField:56.78)", expected: true},
 		31: {condition: "(intField: '123' and strLong: '*Words*') or floatField: 56.78", expected: true},
 		32: {condition: "intField: 78|654|123 and strLong: *Words*|*cucumber* and floatField: 56.78", expected: true},
+		33: {condition: "intField = 123", expected: true},
+		34: {condition: "intField != 123", expected: false},
+		35: {condition: "intField > 100", expected: true},
+		36: {condition: "intField >= 123", expected: true},
+		37: {condition: "intField < 123", expected: false},
+		38: {condition: "intField <= 123.0", expected: true},
+		39: {condition: "floatField>56.7 and intField<1000", expected: true},
+		40: {condition: "intField > 99 and (floatField < 10 or strField = somestring)", expected: true},
+		41: {condition: "strField < t", expected: true},
+		42: {condition: "strField >= 'some'", expected: true},
+		43: {condition: "wrongField != 1", expected: false},
+		44: {condition: "*Field > 100", expected: true},
+		45: {condition: "intField: 123 AND strField: someString", expected: true},
+		46: {condition: "strField ~ '^some(string|thing)$'", expected: true},
+		47: {condition: "strField ~ \"^Some\\w+$\"", expected: true},
+		48: {condition: "strField ~ '(?-i)^Some'", expected: false},
+		49: {condition: "strField ~ '(?-i)^some'", expected: true},
+		50: {condition: "strLong ~ '\\bwords?\\b' and intField ~ '^[0-9]{3}$'", expected: true},
+		51: {condition: "strLong !~ spaces", expected: false},
+		52: {condition: "strLong !~ 'cucumber|tomato'", expected: true},
+		53: {condition: "STRFIELD ~ string", expected: true},
+		54: {condition: "wrongField !~ string", expected: false},
+		55: {condition: "intField = 123 or floatField = 123 and strLong ~ Words", expected: true},
+		56: {condition: "intField: 1 or strField: someString and floatField: 56.78", expected: true},
+		57: {condition: "intField: 1 and strField: someString or floatField: 56.78", expected: true},
+		58: {condition: "intField: 123 or strField: someString and floatField: 1", expected: true},
+		59: {condition: "(intField: 123 or strField: someString) and floatField: 1", expected: false},
+		60: {condition: "intField: 1 and strField: someString or floatField: 1", expected: false},
+		61: {condition: "not intField: 123", expected: false},
+		62: {condition: "NOT intField: 1", expected: true},
+		63: {condition: "not not intField: 123", expected: true},
+		64: {condition: "not (intField: 1 or strField: someString)", expected: false},
+		65: {condition: "not intField: 1 and strField: someString", expected: true},
+		66: {condition: "not intField: 123 or strField: someString", expected: true},
+		67: {condition: "((intField: 123 and (not strField: other or floatField: 1)) or strLong: x) and not floatField < 0", expected: true},
+		68: {condition: "intField:1 or intField:2 or intField:3 or intField:4 or intField:123", expected: true},
+		69: {condition: "exists(intField)", expected: true},
+		70: {condition: "exists(errorField)", expected: false},
+		71: {condition: "missing(errorField)", expected: true},
+		72: {condition: "MISSING(intField)", expected: false},
+		73: {condition: "exists(*long)", expected: true},
+		74: {condition: "is_number(intField) and is_number(floatField) and is_string(strField)", expected: true},
+		75: {condition: "is_number(strField) or is_null(strField)", expected: false},
+		76: {condition: "missing(userId) or is_null(userId)", expected: true},
+		77: {condition: "not exists(errorField) and intField: 123", expected: true},
+		78: {condition: "is_array(*) or is_object(*) or is_bool(*)", expected: false},
+		79: {condition: "someString", expected: true},
+		80: {condition: "SOMESTRING", expected: true},
+		81: {condition: "'words in one'", expected: true},
+		82: {condition: "\"words in two\"", expected: false},
+		83: {condition: "intField:123 and \"string with\"", expected: true},
+		84: {condition: "words and not other", expected: true},
+		85: {condition: "(56.78 or missing) and 123", expected: true},
+		86: {condition: "strlong", expected: false},
+		87: {condition: "intField!=123", expected: false},
+		88: {condition: "intField!=456", expected: true},
+		89: {condition: "strLong!~spaces", expected: false},
+		90: {condition: "strLong!~'cucumber|tomato'", expected: true},
+		91: {condition: "intField:!456|!123", expected: true},
+		92: {condition: "strLong: *cucumber*|!*Words*", expected: false},
 	}
 	for key, cs := range cases {
 		t.Run(strconv.Itoa(key), func(t *testing.T) {
 			factory := NewFactory()
-			filter, err := factory.NewFilter(cs.condition)
+			filter, err := factory.NewFilter(cs.condition, core.DefaultFilterParams())
 			if assert.Nil(t, err, "Error not nil: %s, condition: '%s'", err, cs.condition) {
 				assert.Equal(t, cs.expected, filter.Match(row))
 			}
@@ -101,20 +162,485 @@ func TestFactory_NewFilter(t *testing.T) {
 	}
 }
 
+func TestFactory_NewFilter_SearchFieldNames(t *testing.T) {
+	params := core.DefaultFilterParams()
+	params.SearchFieldNames = true
+	filter, err := NewFactory().NewFilter("strlong and not intField:1", params)
+	if assert.Nil(t, err) {
+		assert.True(t, filter.Match(getRow()))
+	}
+}
+
+func TestFactory_NewFilter_CaseSensitive(t *testing.T) {
+	row := core.Row{Data: map[string]interface{}{
+		"UserID":   "aGVsbG8=",
+		"user_id":  "other",
+		"intField": 123,
+	}}
+	sensitive := core.DefaultFilterParams()
+	sensitive.CaseSensitive = true
+	cases := []struct {
+		condition string
+		params    core.FilterParams
+		expected  bool
+	}{
+		0:  {condition: "UserID: 'aGVsbG8='", params: sensitive, expected: true},
+		1:  {condition: "UserID: 'agvsbg8='", params: sensitive, expected: false},
+		2:  {condition: "userid: 'aGVsbG8='", params: sensitive, expected: false},
+		3:  {condition: "user_id = other", params: sensitive, expected: true},
+		4:  {condition: "USER_ID: other", params: sensitive, expected: false},
+		5:  {condition: "UserID ~ '^aGV'", params: sensitive, expected: true},
+		6:  {condition: "UserID ~ '^agv'", params: sensitive, expected: false},
+		7:  {condition: "exists(USERID)", params: sensitive, expected: false},
+		8:  {condition: "GVsb", params: sensitive, expected: true},
+		9:  {condition: "gvsb", params: sensitive, expected: false},
+		10: {condition: "UserID :/i 'AGVSBG8='", params: sensitive, expected: true},
+		11: {condition: "user_id ~/i '^OTH'", params: sensitive, expected: true},
+		12: {condition: "UserID: 'agvsbg8='", params: core.DefaultFilterParams(), expected: true},
+		13: {condition: "UserID :/c 'agvsbg8='", params: core.DefaultFilterParams(), expected: false},
+		14: {condition: "UserID =/c 'aGVsbG8='", params: core.DefaultFilterParams(), expected: true},
+		15: {condition: "user_id !=/c 'aGVsbG8='", params: core.DefaultFilterParams(), expected: true},
+		16: {condition: "UserID ~/c '^agv'", params: core.DefaultFilterParams(), expected: false},
+		17: {condition: "intField >=/c 100 and intField </i 200", params: core.DefaultFilterParams(), expected: true},
+	}
+	for key, cs := range cases {
+		t.Run(strconv.Itoa(key), func(t *testing.T) {
+			filter, err := NewFactory().NewFilter(cs.condition, cs.params)
+			if assert.Nil(t, err, "Error not nil: %s, condition: '%s'", err, cs.condition) {
+				assert.Equal(t, cs.expected, filter.Match(row))
+			}
+		})
+	}
+}
+
+func TestFactory_NewFilter_Arrays(t *testing.T) {
+	row := core.Row{Data: map[string]interface{}{
+		"tags": []interface{}{"DB", "slow"},
+		"errors": []interface{}{
+			map[string]interface{}{"Code": json.Number("500"), "message": "Timeout"},
+			map[string]interface{}{"Code": json.Number("404"), "message": "Not found"},
+		},
+	}}
+	cases := []struct {
+		condition string
+		expected  bool
+	}{
+		0:  {condition: "tags:db", expected: true},
+		1:  {condition: "tags = slow", expected: true},
+		2:  {condition: "tags:fast", expected: false},
+		3:  {condition: "all(tags): db", expected: false},
+		4:  {condition: "all(tags): db|slow", expected: true},
+		5:  {condition: "ALL(tags) ~ '^[a-z]+$'", expected: true},
+		6:  {condition: "all(tags) ~/c '^[a-z]+$'", expected: false},
+		7:  {condition: "errors[0].code = 500", expected: true},
+		8:  {condition: "errors[1].code = 500", expected: false},
+		9:  {condition: "errors[*].code >= 500", expected: true},
+		10: {condition: "all(errors[*].code) >= 400", expected: true},
+		11: {condition: "all(errors.code) > 404", expected: false},
+		12: {condition: "errors[*].message: timeout and tags: slow", expected: true},
+		13: {condition: "is_array(tags) and is_object(errors[0]) and exists(errors[1].message)", expected: true},
+		14: {condition: "missing(errors[2]) and not exists(tags[5])", expected: true},
+		15: {condition: "'not found'", expected: true},
+		16: {condition: "tags[1]:slow", expected: true},
+	}
+	for key, cs := range cases {
+		t.Run(strconv.Itoa(key), func(t *testing.T) {
+			filter, err := NewFactory().NewFilter(cs.condition, core.DefaultFilterParams())
+			if assert.Nil(t, err, "Error not nil: %s, condition: '%s'", err, cs.condition) {
+				assert.Equal(t, cs.expected, filter.Match(row))
+			}
+		})
+	}
+}
+
+func TestFactory_NewFilter_Time(t *testing.T) {
+	defer stubTimeNow(time.Date(2024, 5, 1, 10, 10, 0, 0, time.UTC))()
+	row := core.Row{Data: map[string]interface{}{
+		"time":     "2024-05-01T10:05:00Z",
+		"ts":       json.Number("1714557900"),
+		"duration": json.Number("150"),
+		"version":  "1.10",
+	}}
+	params := core.DefaultFilterParams()
+	params.Location = time.UTC
+	tsParams := params
+	tsParams.TimeField = "ts"
+	cases := []struct {
+		condition string
+		params    core.FilterParams
+		expected  bool
+	}{
+		0:  {condition: "time > now-15m", params: params, expected: true},
+		1:  {condition: "time > now-2m", params: params, expected: false},
+		2:  {condition: "ts >= now-1h and ts < now", params: params, expected: true},
+		3:  {condition: "time between '2024-05-01T10:00' and '2024-05-01T10:05'", params: params, expected: true},
+		4:  {condition: "ts BETWEEN '2024-05-01T10:06' AND now", params: params, expected: false},
+		5:  {condition: "_time > now-15m", params: params, expected: true},
+		6:  {condition: "_time > now-15m", params: tsParams, expected: true},
+		7:  {condition: "exists(_time) and _time < '2024-05-02'", params: tsParams, expected: true},
+		8:  {condition: "duration between 100 and 200", params: params, expected: true},
+		9:  {condition: "duration between 151 and 200", params: params, expected: false},
+		10: {condition: "version between 1.0 and 1.5", params: params, expected: true},
+		11: {condition: "time between now-10m and now-5m or duration > 1000", params: params, expected: true},
+		12: {condition: "time > now+1m", params: params, expected: false},
+	}
+	for key, cs := range cases {
+		t.Run(strconv.Itoa(key), func(t *testing.T) {
+			filter, err := NewFactory().NewFilter(cs.condition, cs.params)
+			if assert.Nil(t, err, "Error not nil: %s, condition: '%s'", err, cs.condition) {
+				assert.Equal(t, cs.expected, filter.Match(row))
+			}
+		})
+	}
+}
+
+func TestFactory_NewFilter_Level(t *testing.T) {
+	sevParams := core.DefaultFilterParams()
+	sevParams.LevelFields = []string{"sev"}
+	cases := []struct {
+		condition string
+		params    core.FilterParams
+		data      map[string]interface{}
+		expected  bool
+	}{
+		0:  {condition: "level >= warn", data: map[string]interface{}{"level": "ERROR"}, expected: true},
+		1:  {condition: "level >= warn", data: map[string]interface{}{"level": "info"}, expected: false},
+		2:  {condition: "level >= warn", data: map[string]interface{}{"level": json.Number("40")}, expected: true},
+		3:  {condition: "_level >= warn", data: map[string]interface{}{"lvl": "FATAL"}, expected: true},
+		4:  {condition: "_level >= warn", data: map[string]interface{}{"severity": "CRITICAL"}, expected: true},
+		5:  {condition: "level >= warn", data: map[string]interface{}{"message": "text"}, expected: false},
+		6:  {condition: "level = warning", data: map[string]interface{}{"level": "WARN"}, expected: true},
+		7:  {condition: "_level < info", data: map[string]interface{}{"lvl": "debug"}, expected: true},
+		8:  {condition: "level between info and error", data: map[string]interface{}{"level": json.Number("40")}, expected: true},
+		9:  {condition: "level between info and error", data: map[string]interface{}{"level": "fatal"}, expected: false},
+		10: {condition: "sev >= error", params: sevParams, data: map[string]interface{}{"level": "debug", "sev": "fatal"}, expected: true},
+		11: {condition: "_level >= error", params: sevParams, data: map[string]interface{}{"level": "info", "sev": "fatal"}, expected: true},
+		12: {condition: "level >= warn", params: sevParams, data: map[string]interface{}{"level": "error"}, expected: false},
+		13: {condition: "level = 30", data: map[string]interface{}{"level": json.Number("30")}, expected: true},
+		14: {condition: "level: warn*", data: map[string]interface{}{"level": "WARNING"}, expected: true},
+		15: {condition: "level >= warn", data: map[string]interface{}{"lvl": "FATAL"}, expected: false},
+		16: {condition: "severity >= error", data: map[string]interface{}{"level": "info", "severity": "ERROR"}, expected: true},
+		17: {condition: "_level >= error", data: map[string]interface{}{"level": "info", "severity": "ERROR"}, expected: false},
+		18: {condition: "all(level) >= warn", data: map[string]interface{}{"level": []interface{}{"info", "error"}}, expected: false},
+		19: {condition: "all(level) >= warn", data: map[string]interface{}{"level": []interface{}{"warn", "error"}}, expected: true},
+		20: {condition: "severity between warn and error", data: map[string]interface{}{"level": "debug", "severity": "warning"}, expected: true},
+	}
+	for key, cs := range cases {
+		t.Run(strconv.Itoa(key), func(t *testing.T) {
+			params := cs.params
+			if params.TimeField == "" {
+				params = core.DefaultFilterParams()
+			}
+			filter, err := NewFactory().NewFilter(cs.condition, params)
+			if assert.Nil(t, err, "Error not nil: %s, condition: '%s'", err, cs.condition) {
+				assert.Equal(t, cs.expected, filter.Match(core.Row{Data: cs.data}))
+			}
+		})
+	}
+}
+
+func TestFactory_NewFilter_InCidr(t *testing.T) {
+	path, cleanup := writeListForTest(t, "10.20.0.0/16\n# office\n192.168.1.0/24\n")
+	defer cleanup()
+	mockSettings := &core.MockSettings{}
+	mockSettings.On("GetLists").Return(map[string]string{"office": path}, nil)
+	factory := NewFactory()
+	factory.Settings = mockSettings
+	cases := []struct {
+		condition string
+		ip        string
+		expected  bool
+	}{
+		0: {condition: "in_cidr(ip, '10.0.0.0/8')", ip: "10.1.2.3", expected: true},
+		1: {condition: "in_cidr(ip, '10.0.0.0/8')", ip: "11.1.2.3", expected: false},
+		2: {condition: "in_cidr(ip, '10.0.0.0/8', \"fd00::/8\")", ip: "fd00::1", expected: true},
+		3: {condition: "not in_cidr(ip, '10.0.0.0/8', '172.16.0.0/12')", ip: "8.8.8.8", expected: true},
+		4: {condition: "in_cidr(ip, @office)", ip: "192.168.1.10", expected: true},
+		5: {condition: "in_cidr(ip, @office)", ip: "192.168.2.10", expected: false},
+		6: {condition: "in_cidr(ip, '8.8.8.8', @" + path + ")", ip: "10.20.1.1", expected: true},
+		7: {condition: "IN_CIDR(ip, '8.8.8.8') and level: info", ip: "8.8.8.8", expected: true},
+	}
+	for key, cs := range cases {
+		t.Run(strconv.Itoa(key), func(t *testing.T) {
+			filter, err := factory.NewFilter(cs.condition, core.DefaultFilterParams())
+			if assert.Nil(t, err, "Error not nil: %s, condition: '%s'", err, cs.condition) {
+				assert.Equal(t, cs.expected, filter.Match(core.Row{Data: map[string]interface{}{"ip": cs.ip, "level": "info"}}))
+			}
+		})
+	}
+}
+
+func TestFactory_NewFilter_InCidr_InvalidList(t *testing.T) {
+	path, cleanup := writeListForTest(t, "10.20.0.0/16\nwrong\n")
+	defer cleanup()
+	mockSettings := &core.MockSettings{}
+	mockSettings.On("GetLists").Return(map[string]string{"office": path}, nil)
+	factory := NewFactory()
+	factory.Settings = mockSettings
+
+	_, err := factory.NewFilter("in_cidr(ip, @office)", core.DefaultFilterParams())
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Invalid network 'wrong' in list 'office' at column 13, expected CIDR or IP address", err.Error())
+	}
+}
+
+func TestFactory_NewFilter_InSet(t *testing.T) {
+	path, cleanup := writeListForTest(t, "# blocked users\nalice\n\nbob\n")
+	defer cleanup()
+	mockSettings := &core.MockSettings{}
+	mockSettings.On("GetLists").Return(map[string]string{"blocked": path}, nil)
+	factory := NewFactory()
+	factory.Settings = mockSettings
+	cases := []struct {
+		condition string
+		user      string
+		expected  bool
+	}{
+		0: {condition: "user in (alice, 'bob', \"carol\")", user: "Bob", expected: true},
+		1: {condition: "user in (alice, bob)", user: "dave", expected: false},
+		2: {condition: "user IN (alice)", user: "alice", expected: true},
+		3: {condition: "user in/c (alice)", user: "Alice", expected: false},
+		4: {condition: "user in @blocked", user: "bob", expected: true},
+		5: {condition: "user in @blocked", user: "carol", expected: false},
+		6: {condition: "user in @" + path + " and level: info", user: "alice", expected: true},
+		7: {condition: "not user in (alice, bob) or id in (1, 2, 3)", user: "alice", expected: true},
+		8: {condition: "id in (1, 3)", user: "alice", expected: false},
+		// 'in' is operation only after field name, so it still can be name of field
+		9:  {condition: "in:yes", user: "alice", expected: true},
+		10: {condition: "in = no or user: bob", user: "alice", expected: false},
+		11: {condition: "in in (yes, no)", user: "alice", expected: true},
+		12: {condition: "all(in) in (no)", user: "alice", expected: false},
+	}
+	for key, cs := range cases {
+		t.Run(strconv.Itoa(key), func(t *testing.T) {
+			filter, err := factory.NewFilter(cs.condition, core.DefaultFilterParams())
+			if assert.Nil(t, err, "Error not nil: %s, condition: '%s'", err, cs.condition) {
+				row := core.Row{Data: map[string]interface{}{"user": cs.user, "id": json.Number("2"), "level": "info", "in": "yes"}}
+				assert.Equal(t, cs.expected, filter.Match(row))
+			}
+		})
+	}
+}
+
+func newMacrosFactoryForTest() *factory {
+	mockSettings := &core.MockSettings{}
+	mockSettings.On("GetMacros").Return(map[string]string{
+		"no_healthchecks": "not url: '/health*'",
+		"team":            "module in (api, db)",
+		"team_errors":     " @team and (level: error or @slow) ",
+		"slow":            "duration > 1000",
+		"broken":          "url ==",
+		"uses_broken":     "@team and @broken",
+		"unknown":         "@missing",
+		"cycle_a":         "@cycle_b",
+		"cycle_b":         "module: api and @cycle_a",
+		"self":            "not @self",
+	}, nil)
+	factory := NewFactory()
+	factory.Settings = mockSettings
+	return factory
+}
+
+func TestFactory_NewFilter_Macros(t *testing.T) {
+	factory := newMacrosFactoryForTest()
+	cases := []struct {
+		condition string
+		row       map[string]interface{}
+		expected  bool
+	}{
+		0: {condition: "@no_healthchecks and level: error", row: map[string]interface{}{"url": "/api", "level": "error"}, expected: true},
+		1: {condition: "@no_healthchecks and level: error", row: map[string]interface{}{"url": "/health/db", "level": "error"}, expected: false},
+		2: {condition: "@team_errors", row: map[string]interface{}{"module": "db", "level": "error"}, expected: true},
+		3: {condition: "@team_errors", row: map[string]interface{}{"module": "db", "level": "info", "duration": json.Number("1500")}, expected: true},
+		4: {condition: "@team_errors", row: map[string]interface{}{"module": "web", "level": "error"}, expected: false},
+		5: {condition: "not @slow or @slow", row: map[string]interface{}{}, expected: true},
+		// macro is parsed as expression in brackets
+		6: {condition: "@no_healthchecks or @no_healthchecks and module: web", row: map[string]interface{}{"url": "/api"}, expected: true},
+	}
+	for key, cs := range cases {
+		t.Run(strconv.Itoa(key), func(t *testing.T) {
+			filter, err := factory.NewFilter(cs.condition, core.DefaultFilterParams())
+			if assert.Nil(t, err, "Error not nil: %s, condition: '%s'", err, cs.condition) {
+				assert.Equal(t, cs.expected, filter.Match(core.Row{Data: cs.row}))
+			}
+		})
+	}
+}
+
+func TestFactory_NewFilter_Macros_Errors(t *testing.T) {
+	cases := []struct {
+		condition string
+		expected  core.ConditionError
+	}{
+		0: {
+			condition: "level: error and @nothing",
+			expected:  core.ConditionError{Condition: "level: error and @nothing", Column: 18, Message: "Unknown macro 'nothing'"},
+		},
+		1: {
+			condition: "@broken",
+			expected:  core.ConditionError{Condition: "url ==", Column: 6, Message: "Unexpected token '='", Expected: "field value", Macro: "broken"},
+		},
+		2: {
+			condition: "@uses_broken",
+			expected:  core.ConditionError{Condition: "url ==", Column: 6, Message: "Unexpected token '='", Expected: "field value", Macro: "broken"},
+		},
+		3: {
+			condition: "@unknown",
+			expected:  core.ConditionError{Condition: "@missing", Column: 1, Message: "Unknown macro 'missing'", Macro: "unknown"},
+		},
+		4: {
+			condition: "@cycle_a",
+			expected:  core.ConditionError{Condition: "module: api and @cycle_a", Column: 17, Message: "Cycle of macros: cycle_a -> cycle_b -> cycle_a", Macro: "cycle_b"},
+		},
+		5: {
+			condition: "level: error or @self",
+			expected:  core.ConditionError{Condition: "not @self", Column: 5, Message: "Cycle of macros: self -> self", Macro: "self"},
+		},
+	}
+	factory := newMacrosFactoryForTest()
+	for key, cs := range cases {
+		t.Run(strconv.Itoa(key), func(t *testing.T) {
+			_, err := factory.NewFilter(cs.condition, core.DefaultFilterParams())
+			if assert.NotNil(t, err) {
+				assert.Equal(t, &cs.expected, err)
+			}
+		})
+	}
+}
+
+func TestFactory_NewFilter_Macros_WithoutSettings(t *testing.T) {
+	_, err := NewFactory().NewFilter("@no_healthchecks", core.DefaultFilterParams())
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Unknown macro 'no_healthchecks' at column 1", err.Error())
+	}
+}
+
+func TestFactory_NewFilter_Priorities(t *testing.T) {
+	filter, err := NewFactory().NewFilter("a:1 or not b:2 and c:3", core.DefaultFilterParams())
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	or, ok := filter.(*Or)
+	if assert.True(t, ok, "root must be Or, got %T", filter) {
+		and, ok := or.Right.(*And)
+		if assert.True(t, ok, "right must be And, got %T", or.Right) {
+			assert.IsType(t, &Not{}, and.Left)
+		}
+	}
+}
+
+func TestFactory_NewFilter_ManyOperations(t *testing.T) {
+	checks := make([]string, 0, 300)
+	for i := 0; i < 300; i++ {
+		checks = append(checks, "intField: "+strconv.Itoa(i))
+	}
+	filter, err := NewFactory().NewFilter(strings.Join(checks, " or "), core.DefaultFilterParams())
+	if assert.Nil(t, err) {
+		assert.True(t, filter.Match(getRow()))
+	}
+}
+
 func TestFactory_NewFilter_SyntaxError(t *testing.T) {
-	errorConditions := []string{
-		"intField = 123 or floatField = 123 and strLong ~ Words",
-		"intField or floatField = 123 and strLong ~ Words",
-		"intField == 123",
-		"intField floatField = 123",
-		"intField ( floatField = 123 and strLong ~ Words)",
-		"intField or ( floatField = 123 and strLong ~ Words",
-	}
-	for key, cond := range errorConditions {
+	cases := []struct {
+		condition string
+		column    int
+		expected  string
+	}{
+		0:  {condition: "intField: or floatField = 123 and strLong ~ Words", column: 11, expected: "field value"},
+		1:  {condition: "intField == 123", column: 11, expected: "field value"},
+		2:  {condition: "intField floatField = 123", column: 10, expected: "logic operation"},
+		3:  {condition: "intField ( floatField = 123 and strLong ~ Words)", column: 23, expected: "comma or close bracket"},
+		4:  {condition: "intField or ( floatField = 123 and strLong ~ Words", column: 51, expected: "logic operation or close bracket"},
+		5:  {condition: "intField: 1 and", column: 16, expected: expectedOperand},
+		6:  {condition: "not", column: 4, expected: expectedOperand},
+		7:  {condition: "intField: 1 not strField: 2", column: 13, expected: "logic operation"},
+		8:  {condition: "(intField: 1))", column: 14, expected: "logic operation"},
+		9:  {condition: "intField: 1 and or strField: 2", column: 17, expected: expectedOperand},
+		10: {condition: "()", column: 2, expected: expectedOperand},
+		11: {condition: "exists()", column: 8, expected: "argument of function"},
+		12: {condition: "exists(a, b)", column: 1},
+		13: {condition: "exists(a b)", column: 10, expected: "comma or close bracket"},
+		14: {condition: "exists(a", column: 9, expected: "comma or close bracket"},
+		15: {condition: "unknown(a)", column: 1},
+		16: {condition: "intField :/x 1", column: 11, expected: "modifier '/c' or '/i'"},
+		17: {condition: "all(a, b): 1", column: 1},
+		18: {condition: "all(a) and b:1", column: 8, expected: expectedFieldOperation},
+		19: {condition: "intField: 123 and strField ~ 'some(string'", column: 30},
+		20: {condition: "strField: 'some", column: 11, expected: "closing quote"},
+		21: {condition: "strField: 'значение' and a ^ 2", column: 28},
+		22: {condition: "  intField ==", column: 11, expected: "field value"},
+		23: {condition: "time between now", column: 17, expected: "'and'"},
+		24: {condition: "time between now or now-1m", column: 18, expected: "'and'"},
+		25: {condition: "time between now and", column: 21, expected: "field value"},
+		26: {condition: "in_cidr(ip)", column: 1},
+		27: {condition: "in_cidr(ip, '10.0.0.0/33')", column: 13, expected: "CIDR or IP address"},
+		28: {condition: "in_cidr(ip, @/not/exists.txt)", column: 13},
+		29: {condition: "exists(@ip)", column: 8, expected: "field name"},
+		30: {condition: "user in alice", column: 9, expected: expectedInValues},
+		31: {condition: "user in ()", column: 10, expected: "field value"},
+		32: {condition: "user in (a b)", column: 12, expected: "comma or close bracket"},
+		33: {condition: "user in (a,", column: 12, expected: "field value"},
+		34: {condition: "user in", column: 8, expected: expectedInValues},
+		35: {condition: "user in @/not/exists.txt", column: 9},
+	}
+	for key, cs := range cases {
 		t.Run(strconv.Itoa(key), func(t *testing.T) {
-			factory := NewFactory()
-			_, err := factory.NewFilter(cond)
-			assert.NotNil(t, err)
+			_, err := NewFactory().NewFilter(cs.condition, core.DefaultFilterParams())
+			if assert.NotNil(t, err) {
+				condErr, ok := err.(*core.ConditionError)
+				if assert.True(t, ok, "Unexpected error type: %T", err) {
+					assert.Equal(t, strings.TrimSpace(cs.condition), condErr.Condition)
+					assert.Equal(t, cs.column, condErr.Column, condErr.Message)
+					assert.Equal(t, cs.expected, condErr.Expected)
+				}
+			}
+		})
+	}
+}
+
+func TestFactory_NewFilter_RegexError(t *testing.T) {
+	for key, condition := range []string{"strField ~ '(abc'", "strField ~/c '(abc'"} {
+		t.Run(strconv.Itoa(key), func(t *testing.T) {
+			_, err := NewFactory().NewFilter(condition, core.DefaultFilterParams())
+			if assert.NotNil(t, err) {
+				assert.Equal(t, "Invalid regular expression: error parsing regexp: missing closing ): `(abc`", err.(*core.ConditionError).Message)
+			}
+		})
+	}
+}
+
+func BenchmarkFilter_Match(b *testing.B) {
+	row := core.Row{Data: map[string]interface{}{
+		"time":     "2017-09-28T10:00:00Z",
+		"Level":    "Error",
+		"message":  "Connection reset by peer while reading response",
+		"duration": json.Number("1532"),
+		"userId":   json.Number("42"),
+		"tags":     []interface{}{"DB", "slow"},
+		"request":  map[string]interface{}{"method": "GET", "url": "/api/v1/users"},
+	}}
+	conditions := []string{
+		"level: error",
+		"level: warn|error and message: *reset*",
+		"duration > 1000 and not userId = 7",
+		"message ~ 'reset|refused'",
+		"*: *peer*",
+		"'connection reset'",
+		"tags: db and request.method = get",
+		"exists(userId) and missing(errorCode)",
+		"level >= warn",
+		"userId in (7, 42, 100) and tags in (db, cache)",
+	}
+	for _, condition := range conditions {
+		filter, err := NewFactory().NewFilter(condition, core.DefaultFilterParams())
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(condition, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				filter.Match(row)
+			}
 		})
 	}
 }
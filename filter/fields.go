@@ -0,0 +1,176 @@
+package filter
+
+import (
+	"strconv"
+	"strings"
+)
+
+// NewField creates selector of row fields by name.
+// Name can be wildcard or path like 'errors[0].code', 'errors[*].code', 'user.name'.
+// If all is true, every selected value and every element of selected arrays must be matched.
+func NewField(name string, caseSensitive, all bool) Field {
+	path := parsePath(name)
+	return Field{
+		name:          name,
+		pattern:       compileGlob(name, caseSensitive),
+		isWildcard:    path == nil && strings.Contains(name, "*"),
+		caseSensitive: caseSensitive,
+		all:           all,
+		path:          path,
+	}
+}
+
+// Field selects fields of row by name
+type Field struct {
+	name          string
+	pattern       globPattern
+	isWildcard    bool
+	caseSensitive bool
+	all           bool
+	// path is nil if name doesn't contain nested keys or indexes
+	path []pathSegment
+}
+
+type pathSegment struct {
+	key string
+	// index of array element, -1 means any element
+	index   int
+	isIndex bool
+}
+
+// parsePath splits name like 'errors[0].code' to segments, nil is returned for name without path
+func parsePath(name string) []pathSegment {
+	if !strings.ContainsAny(name, ".[") {
+		return nil
+	}
+	path := []pathSegment{}
+	for _, part := range strings.Split(name, ".") {
+		bracket := strings.IndexByte(part, '[')
+		if bracket < 0 {
+			bracket = len(part)
+		}
+		if bracket == 0 {
+			return nil
+		}
+		path = append(path, pathSegment{key: part[:bracket]})
+		for rest := part[bracket:]; rest != ""; {
+			end := strings.IndexByte(rest, ']')
+			if rest[0] != '[' || end < 0 {
+				return nil
+			}
+			segment := pathSegment{index: -1, isIndex: true}
+			if rest[1:end] != "*" {
+				index, err := strconv.Atoi(rest[1:end])
+				if err != nil || index < 0 {
+					return nil
+				}
+				segment.index = index
+			}
+			path = append(path, segment)
+			rest = rest[end+1:]
+		}
+	}
+	return path
+}
+
+// visit calls fn for values of row fields selected by field, until fn returns false.
+// Names of fields are compared case insensitive, if field is not case sensitive.
+// Path is used only if row doesn't contain field with such name.
+func (f Field) visit(data map[string]interface{}, fn func(value interface{}) bool) {
+	if f.isWildcard {
+		for rowField, rowValue := range data {
+			if f.pattern.match(rowField) && !fn(rowValue) {
+				return
+			}
+		}
+		return
+	}
+	if rowValue, ok := f.lookup(data, f.name); ok {
+		fn(rowValue)
+		return
+	}
+	if f.path != nil {
+		f.visitPath(data, f.path, fn)
+	}
+}
+
+func (f Field) lookup(data map[string]interface{}, key string) (interface{}, bool) {
+	if value, ok := data[key]; ok {
+		return value, true
+	}
+	if f.caseSensitive {
+		return nil, false
+	}
+	for rowField, rowValue := range data {
+		if strings.EqualFold(key, rowField) {
+			return rowValue, true
+		}
+	}
+	return nil, false
+}
+
+// visitPath walks by path segments, key segment is applied to every element of array
+func (f Field) visitPath(value interface{}, path []pathSegment, fn func(value interface{}) bool) bool {
+	if len(path) == 0 {
+		return fn(value)
+	}
+	segment := path[0]
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if segment.isIndex {
+			return true
+		}
+		if nested, ok := f.lookup(v, segment.key); ok {
+			return f.visitPath(nested, path[1:], fn)
+		}
+	case []interface{}:
+		if !segment.isIndex {
+			for _, element := range v {
+				if !f.visitPath(element, path, fn) {
+					return false
+				}
+			}
+			return true
+		}
+		if segment.index < 0 {
+			for _, element := range v {
+				if !f.visitPath(element, path[1:], fn) {
+					return false
+				}
+			}
+			return true
+		}
+		if segment.index < len(v) {
+			return f.visitPath(v[segment.index], path[1:], fn)
+		}
+	}
+	return true
+}
+
+// matchField matches values of row fields selected by field, arrays are matched by their elements.
+// Any matched value is enough by default, in mode 'all' every value must be matched.
+// Field, which is not found or is empty array, is never matched.
+func matchField(data map[string]interface{}, field Field, match func(value interface{}) bool) bool {
+	found := false
+	result := field.all
+	matchValue := func(value interface{}) bool {
+		found = true
+		if match(value) != field.all {
+			result = !field.all
+			return false
+		}
+		return true
+	}
+	field.visit(data, func(value interface{}) bool {
+		if elements, ok := value.([]interface{}); ok {
+			for _, element := range elements {
+				if !matchValue(element) {
+					return false
+				}
+			}
+			return true
+		}
+		return matchValue(value)
+	})
+	return found && result
+}
@@ -0,0 +1,81 @@
+package filter
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+)
+
+func TestMatchField(t *testing.T) {
+	data := map[string]interface{}{
+		"tags":  []interface{}{"db", "slow"},
+		"empty": []interface{}{},
+		"errors": []interface{}{
+			map[string]interface{}{"code": "e1", "Details": map[string]interface{}{"line": "10"}},
+			map[string]interface{}{"code": "e2"},
+		},
+		"user":        map[string]interface{}{"name": "john"},
+		"http.status": "200",
+		"matrix":      []interface{}{[]interface{}{"a", "b"}},
+	}
+	cases := []struct {
+		field    string
+		all      bool
+		val      string
+		expected bool
+	}{
+		{field: "tags", val: "db", expected: true},
+		{field: "tags", val: "slow", expected: true},
+		{field: "tags", val: "fast", expected: false},
+		{field: "tags", all: true, val: "db", expected: false},
+		{field: "empty", val: "db", expected: false},
+		{field: "empty", all: true, val: "db", expected: false},
+		{field: "tags[0]", val: "db", expected: true},
+		{field: "tags[1]", val: "db", expected: false},
+		{field: "tags[2]", val: "db", expected: false},
+		{field: "tags[*]", val: "slow", expected: true},
+		{field: "errors[0].code", val: "e1", expected: true},
+		{field: "errors[1].code", val: "e1", expected: false},
+		{field: "errors[*].code", val: "e2", expected: true},
+		{field: "errors[*].code", all: true, val: "e2", expected: false},
+		{field: "errors.code", val: "e2", expected: true},
+		{field: "errors[0].details.line", val: "10", expected: true},
+		{field: "errors[0].code.wrong", val: "e1", expected: false},
+		{field: "user.name", val: "john", expected: true},
+		{field: "user[0]", val: "john", expected: false},
+		{field: "http.status", val: "200", expected: true},
+		{field: "matrix[0][1]", val: "b", expected: true},
+		{field: "matrix[0][x]", val: "b", expected: false},
+		{field: "t*", val: "slow", expected: true},
+		{field: "wrong.path", val: "x", expected: false},
+	}
+	for k, cs := range cases {
+		t.Run(strconv.Itoa(k), func(t *testing.T) {
+			field := NewField(cs.field, false, cs.all)
+			actual := matchField(data, field, func(value interface{}) bool {
+				return value == cs.val
+			})
+			assert.Equal(t, cs.expected, actual)
+		})
+	}
+}
+
+func TestMatchField_All(t *testing.T) {
+	data := map[string]interface{}{
+		"tags":   []interface{}{"db", "db"},
+		"single": "db",
+	}
+	isDb := func(value interface{}) bool { return value == "db" }
+	assert.True(t, matchField(data, NewField("tags", false, true), isDb))
+	assert.True(t, matchField(data, NewField("single", false, true), isDb))
+	assert.True(t, matchField(data, NewField("*", false, true), isDb))
+	assert.False(t, matchField(data, NewField("wrong", false, true), isDb))
+}
+
+func TestParsePath(t *testing.T) {
+	assert.Nil(t, parsePath("simple"))
+	assert.Nil(t, parsePath("[0].code"))
+	assert.Nil(t, parsePath("a[-1]"))
+	assert.Nil(t, parsePath("a[1"))
+	assert.Equal(t, []pathSegment{{key: "a"}, {index: 2, isIndex: true}, {key: "b"}, {index: -1, isIndex: true}}, parsePath("a[2].b[*]"))
+}
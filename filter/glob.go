@@ -0,0 +1,49 @@
+package filter
+
+import "strings"
+
+// compileGlob splits wildcard pattern with '*' once, so it is matched without allocations
+func compileGlob(pattern string, caseSensitive bool) globPattern {
+	return globPattern{
+		parts:         strings.Split(pattern, "*"),
+		caseSensitive: caseSensitive,
+	}
+}
+
+type globPattern struct {
+	parts         []string
+	caseSensitive bool
+}
+
+func (g globPattern) match(s string) bool {
+	if len(g.parts) == 1 {
+		return g.equal(s, g.parts[0])
+	}
+	first, last := g.parts[0], g.parts[len(g.parts)-1]
+	if len(s) < len(first) || !g.equal(s[:len(first)], first) {
+		return false
+	}
+	s = s[len(first):]
+	for _, part := range g.parts[1 : len(g.parts)-1] {
+		i := g.index(s, part)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(part):]
+	}
+	return len(s) >= len(last) && g.equal(s[len(s)-len(last):], last)
+}
+
+func (g globPattern) equal(a, b string) bool {
+	if g.caseSensitive {
+		return a == b
+	}
+	return strings.EqualFold(a, b)
+}
+
+func (g globPattern) index(s, substr string) int {
+	if g.caseSensitive {
+		return strings.Index(s, substr)
+	}
+	return indexFold(s, substr)
+}
@@ -0,0 +1,39 @@
+package filter
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+)
+
+func TestGlobPattern_Match(t *testing.T) {
+	cases := []struct {
+		pattern       string
+		caseSensitive bool
+		value         string
+		expected      bool
+	}{
+		{pattern: "", value: "", expected: true},
+		{pattern: "", value: "a", expected: false},
+		{pattern: "*", value: "", expected: true},
+		{pattern: "*", value: "anything", expected: true},
+		{pattern: "some", value: "some", expected: true},
+		{pattern: "some", value: "SOME", expected: true},
+		{pattern: "some", caseSensitive: true, value: "SOME", expected: false},
+		{pattern: "some*", value: "SomeString", expected: true},
+		{pattern: "*string", value: "SomeString", expected: true},
+		{pattern: "*meStr*", value: "SomeString", expected: true},
+		{pattern: "*mestr*", caseSensitive: true, value: "SomeString", expected: false},
+		{pattern: "so*ing", value: "SomeString", expected: true},
+		{pattern: "so*o*ing", value: "SomeString", expected: false},
+		{pattern: "a*a", value: "a", expected: false},
+		{pattern: "a*b*c", value: "aXbYc", expected: true},
+		{pattern: "a*b*c", value: "aXcYb", expected: false},
+		{pattern: "*ПРИВЕТ*", value: "скажи привет миру", expected: true},
+	}
+	for k, cs := range cases {
+		t.Run(strconv.Itoa(k), func(t *testing.T) {
+			assert.Equal(t, cs.expected, compileGlob(cs.pattern, cs.caseSensitive).match(cs.value))
+		})
+	}
+}
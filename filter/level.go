@@ -0,0 +1,49 @@
+package filter
+
+import (
+	"github.com/voronelf/logview/core"
+	"github.com/voronelf/logview/severity"
+)
+
+// NewLevelCompare creates filter which compares normalized severity level of field value with level.
+// Levels are set only for alias '_level', then level is taken from first found level field of row instead of field.
+func NewLevelCompare(field Field, levels *severity.Normalizer, operation string, level severity.Level) *levelCompare {
+	return &levelCompare{field: field, levels: levels, operation: operation, bounds: []severity.Level{level}}
+}
+
+// NewLevelBetween creates filter which matches normalized severity level between from and to inclusive
+func NewLevelBetween(field Field, levels *severity.Normalizer, from, to severity.Level) *levelCompare {
+	return &levelCompare{field: field, levels: levels, operation: opBetween, bounds: []severity.Level{from, to}}
+}
+
+type levelCompare struct {
+	field     Field
+	levels    *severity.Normalizer
+	operation string
+	bounds    []severity.Level
+}
+
+var _ core.Filter = (*levelCompare)(nil)
+
+// Match never matches rows without level or with unknown level
+func (c *levelCompare) Match(row core.Row) bool {
+	if c.levels == nil {
+		return matchField(row.Data, c.field, c.matchRowValue)
+	}
+	_, level, ok := c.levels.Find(row.Data)
+	return ok && c.matchLevel(level)
+}
+
+func (c *levelCompare) matchRowValue(rowValue interface{}) bool {
+	return c.matchLevel(severity.Parse(rowValue))
+}
+
+func (c *levelCompare) matchLevel(level severity.Level) bool {
+	if level == severity.Unknown {
+		return false
+	}
+	if c.operation == opBetween {
+		return level >= c.bounds[0] && level <= c.bounds[1]
+	}
+	return matchComparison(c.operation, int(level-c.bounds[0]))
+}
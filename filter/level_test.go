@@ -0,0 +1,50 @@
+package filter
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/voronelf/logview/core"
+	"github.com/voronelf/logview/severity"
+	"strconv"
+	"testing"
+)
+
+func TestLevelCompare_Match(t *testing.T) {
+	levels := severity.NewNormalizer(nil)
+	level := NewField("level", false, false)
+	all := NewField("level", false, true)
+	alias := NewField(levelFieldAlias, false, false)
+	cases := []struct {
+		filter   *levelCompare
+		data     map[string]interface{}
+		expected bool
+	}{
+		{NewLevelCompare(level, nil, opGreaterOrEqual, severity.Warn), map[string]interface{}{"level": "warning"}, true},
+		{NewLevelCompare(level, nil, opGreaterOrEqual, severity.Warn), map[string]interface{}{"level": "ERROR"}, true},
+		{NewLevelCompare(level, nil, opGreaterOrEqual, severity.Warn), map[string]interface{}{"level": "info"}, false},
+		{NewLevelCompare(level, nil, opGreaterOrEqual, severity.Warn), map[string]interface{}{"level": float64(50)}, true},
+		{NewLevelCompare(level, nil, opGreaterOrEqual, severity.Warn), map[string]interface{}{"Level": "fatal"}, true},
+		{NewLevelCompare(level, nil, opGreaterOrEqual, severity.Warn), map[string]interface{}{"level": "info", "severity": "CRITICAL"}, false},
+		{NewLevelCompare(alias, levels, opGreaterOrEqual, severity.Warn), map[string]interface{}{"lvl": "fatal"}, true},
+		{NewLevelCompare(alias, levels, opGreaterOrEqual, severity.Warn), map[string]interface{}{"severity": "CRITICAL"}, true},
+		{NewLevelCompare(alias, levels, opGreaterOrEqual, severity.Warn), map[string]interface{}{"level": "info", "severity": "CRITICAL"}, false},
+		{NewLevelCompare(all, nil, opGreaterOrEqual, severity.Warn), map[string]interface{}{"level": []interface{}{"warn", "error"}}, true},
+		{NewLevelCompare(all, nil, opGreaterOrEqual, severity.Warn), map[string]interface{}{"level": []interface{}{"info", "error"}}, false},
+		{NewLevelCompare(level, nil, opGreaterOrEqual, severity.Warn), map[string]interface{}{"level": []interface{}{"info", "error"}}, true},
+		{NewLevelCompare(level, nil, opGreaterOrEqual, severity.Warn), map[string]interface{}{"level": "custom"}, false},
+		{NewLevelCompare(level, nil, opGreaterOrEqual, severity.Warn), map[string]interface{}{"message": "text"}, false},
+		{NewLevelCompare(level, nil, opLess, severity.Warn), map[string]interface{}{"level": float64(30)}, true},
+		{NewLevelCompare(level, nil, opEqual, severity.Warn), map[string]interface{}{"level": "WARNING"}, true},
+		{NewLevelCompare(level, nil, opNotEqual, severity.Warn), map[string]interface{}{"level": "warn"}, false},
+		{NewLevelCompare(level, nil, opNotEqual, severity.Warn), map[string]interface{}{"message": "text"}, false},
+		{NewLevelBetween(level, nil, severity.Info, severity.Error), map[string]interface{}{"level": "warn"}, true},
+		{NewLevelBetween(level, nil, severity.Info, severity.Error), map[string]interface{}{"level": "info"}, true},
+		{NewLevelBetween(level, nil, severity.Info, severity.Error), map[string]interface{}{"level": "debug"}, false},
+		{NewLevelBetween(level, nil, severity.Info, severity.Error), map[string]interface{}{"level": float64(60)}, false},
+		{NewLevelCompare(alias, severity.NewNormalizer([]string{"sev"}), opGreater, severity.Info), map[string]interface{}{"level": "error", "sev": "debug"}, false},
+	}
+	for i, cs := range cases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			assert.Equal(t, cs.expected, cs.filter.Match(core.Row{Data: cs.data}))
+		})
+	}
+}
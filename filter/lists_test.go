@@ -0,0 +1,47 @@
+package filter
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeListForTest creates temporary file with content and returns its path and cleanup function
+func writeListForTest(t *testing.T, content string) (string, func()) {
+	dir, err := ioutil.TempDir("", "logview_test_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "list.txt")
+	err = ioutil.WriteFile(path, []byte(content), 0664)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path, func() { os.RemoveAll(dir) }
+}
+
+func TestReadList(t *testing.T) {
+	path, cleanup := writeListForTest(t, "# office ranges\n10.0.0.0/8\n\n  192.168.1.15  \r\n  # printer\norder#123\nfd00::/8")
+	defer cleanup()
+
+	actual, err := readList(path)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"10.0.0.0/8", "192.168.1.15", "order#123", "fd00::/8"}, actual)
+
+	_, err = readList(path + ".not-exists")
+	assert.NotNil(t, err)
+}
+
+func TestReadList_HomeDirectory(t *testing.T) {
+	path, cleanup := writeListForTest(t, "a\nb")
+	defer cleanup()
+	home := os.Getenv("HOME")
+	defer os.Setenv("HOME", home)
+	os.Setenv("HOME", filepath.Dir(path))
+
+	actual, err := readList("~/list.txt")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b"}, actual)
+}
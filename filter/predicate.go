@@ -0,0 +1,68 @@
+package filter
+
+import (
+	"encoding/json"
+	"github.com/voronelf/logview/core"
+)
+
+// valuePredicates contains checks of field value by predicate name
+var valuePredicates = map[string]func(value interface{}) bool{
+	"exists": func(value interface{}) bool {
+		return true
+	},
+	"is_null": func(value interface{}) bool {
+		return value == nil
+	},
+	"is_number": func(value interface{}) bool {
+		switch value.(type) {
+		case float64, json.Number:
+			return true
+		}
+		return false
+	},
+	"is_string": func(value interface{}) bool {
+		_, ok := value.(string)
+		return ok
+	},
+	"is_bool": func(value interface{}) bool {
+		_, ok := value.(bool)
+		return ok
+	},
+	"is_array": func(value interface{}) bool {
+		_, ok := value.([]interface{})
+		return ok
+	},
+	"is_object": func(value interface{}) bool {
+		_, ok := value.(map[string]interface{})
+		return ok
+	},
+}
+
+// NewPredicate creates filter which matches rows with field value satisfying predicate.
+// Arrays are checked as whole values. Returns nil if predicate is unknown.
+func NewPredicate(name string, field Field) *predicate {
+	check, ok := valuePredicates[name]
+	if !ok {
+		return nil
+	}
+	return &predicate{
+		field: field,
+		check: check,
+	}
+}
+
+type predicate struct {
+	field Field
+	check func(value interface{}) bool
+}
+
+var _ core.Filter = (*predicate)(nil)
+
+func (p *predicate) Match(row core.Row) bool {
+	matched := false
+	p.field.visit(row.Data, func(value interface{}) bool {
+		matched = p.check(value)
+		return !matched
+	})
+	return matched
+}
@@ -0,0 +1,58 @@
+package filter
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/voronelf/logview/core"
+	"strconv"
+	"testing"
+)
+
+func TestPredicate_Match(t *testing.T) {
+	row := core.Row{Data: map[string]interface{}{
+		"str":    "value",
+		"float":  float64(1.5),
+		"number": json.Number("123"),
+		"null":   nil,
+		"bool":   false,
+		"array":  []interface{}{"a"},
+		"Object": map[string]interface{}{"a": "b"},
+	}}
+	cases := []struct {
+		name     string
+		field    string
+		expected bool
+	}{
+		{"exists", "str", true},
+		{"exists", "null", true},
+		{"exists", "wrong", false},
+		{"exists", "obj*", true},
+		{"exists", "wrong*", false},
+		{"is_null", "null", true},
+		{"is_null", "str", false},
+		{"is_null", "wrong", false},
+		{"is_number", "float", true},
+		{"is_number", "number", true},
+		{"is_number", "str", false},
+		{"is_string", "str", true},
+		{"is_string", "number", false},
+		{"is_bool", "bool", true},
+		{"is_array", "array", true},
+		{"is_array", "str", false},
+		{"is_object", "object", true},
+		{"is_object", "*", true},
+		{"is_object", "array", false},
+	}
+	for k, cs := range cases {
+		t.Run(strconv.Itoa(k), func(t *testing.T) {
+			f := NewPredicate(cs.name, NewField(cs.field, false, false))
+			if assert.NotNil(t, f) {
+				assert.Equal(t, cs.expected, f.Match(row))
+			}
+		})
+	}
+}
+
+func TestNewPredicate_Unknown(t *testing.T) {
+	assert.Nil(t, NewPredicate("is_wrong", NewField("field", false, false)))
+}
@@ -0,0 +1,32 @@
+package filter
+
+import (
+	"github.com/voronelf/logview/core"
+	"regexp"
+)
+
+// NewRegex creates filter which checks field value by regular expression.
+// If negative is true, filter matches fields with value not matched by expression.
+func NewRegex(field Field, expr *regexp.Regexp, negative bool) *regex {
+	return &regex{
+		field:    field,
+		expr:     expr,
+		negative: negative,
+	}
+}
+
+type regex struct {
+	field    Field
+	expr     *regexp.Regexp
+	negative bool
+}
+
+var _ core.Filter = (*regex)(nil)
+
+func (r *regex) Match(row core.Row) bool {
+	return matchField(row.Data, r.field, r.matchRowValue)
+}
+
+func (r *regex) matchRowValue(rowValue interface{}) bool {
+	return r.expr.MatchString(toString(rowValue)) != r.negative
+}
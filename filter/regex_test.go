@@ -0,0 +1,36 @@
+package filter
+
+import (
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+func TestRegex_Match(t *testing.T) {
+	cases := []struct {
+		field    string
+		expr     string
+		negative bool
+		expected bool
+	}{
+		{field: "strField", expr: "^some", expected: true},
+		{field: "strField", expr: "^Some", expected: false},
+		{field: "strField", expr: "(?i)^Some", expected: true},
+		{field: "StrField", expr: "String$", expected: true},
+		{field: "strField", expr: "String$", negative: true, expected: false},
+		{field: "strField", expr: "other", negative: true, expected: true},
+		{field: "intField", expr: "^1[0-9]+$", expected: true},
+		{field: "floatField", expr: `^\d+\.\d+$`, expected: true},
+		{field: "*Field", expr: `^\d+\.\d+$`, expected: true},
+		{field: "*Long", expr: `\d`, expected: false},
+		{field: "wrongField", expr: ".*", expected: false},
+		{field: "wrongField", expr: ".*", negative: true, expected: false},
+	}
+	for k, cs := range cases {
+		t.Run(strconv.Itoa(k), func(t *testing.T) {
+			f := NewRegex(NewField(cs.field, false, false), regexp.MustCompile(cs.expr), cs.negative)
+			assert.Equal(t, cs.expected, f.Match(getRow()))
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package filter
+
+import (
+	"encoding/json"
+	"github.com/voronelf/logview/core"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxFoldBuffer is length of values, which are lower-cased for lookup without allocations
+const maxFoldBuffer = 128
+
+// maxExactFloatInt is maximal integer, which is represented by float64 exactly
+const maxExactFloatInt = 1 << 53
+
+// NewInSet creates filter which matches field value equal to any of values.
+// Values are kept in hash set, numbers are compared also as numbers, e.g. '2' is equal to '2.0'.
+func NewInSet(field Field, values []string) *inSet {
+	s := &inSet{
+		field:   field,
+		values:  make(map[string]struct{}, len(values)),
+		numbers: map[float64]struct{}{},
+	}
+	for _, value := range values {
+		if !field.caseSensitive {
+			value = strings.ToLower(value)
+		}
+		s.values[value] = struct{}{}
+		if number, ok := parseNumber(value); ok && isExactFloat(number) {
+			s.numbers[number.floatValue] = struct{}{}
+		}
+	}
+	return s
+}
+
+type inSet struct {
+	field   Field
+	values  map[string]struct{}
+	numbers map[float64]struct{}
+}
+
+var _ core.Filter = (*inSet)(nil)
+
+func (s *inSet) Match(row core.Row) bool {
+	return matchField(row.Data, s.field, s.matchRowValue)
+}
+
+func (s *inSet) matchRowValue(rowValue interface{}) bool {
+	switch value := rowValue.(type) {
+	case string:
+		return s.hasString(value)
+	case json.Number:
+		if s.hasString(string(value)) {
+			return true
+		}
+		number, ok := parseNumber(string(value))
+		return ok && isExactFloat(number) && s.hasNumber(number.floatValue)
+	case float64:
+		return s.hasNumber(value)
+	case bool:
+		return s.hasString(strconv.FormatBool(value))
+	case nil:
+		return s.hasString("null")
+	}
+	return false
+}
+
+// isExactFloat checks that integer is not rounded as float, so big ids are compared only as strings
+func isExactFloat(n number) bool {
+	return !n.isInt || (n.intValue <= maxExactFloatInt && n.intValue >= -maxExactFloatInt)
+}
+
+func (s *inSet) hasNumber(number float64) bool {
+	_, ok := s.numbers[number]
+	return ok
+}
+
+// hasString looks up value, short ASCII values are lower-cased in buffer on stack for case insensitive lookup
+func (s *inSet) hasString(value string) bool {
+	if s.field.caseSensitive {
+		_, ok := s.values[value]
+		return ok
+	}
+	var buf [maxFoldBuffer]byte
+	if len(value) > len(buf) {
+		_, ok := s.values[strings.ToLower(value)]
+		return ok
+	}
+	for i := 0; i < len(value); i++ {
+		if value[i] >= utf8.RuneSelf {
+			_, ok := s.values[strings.ToLower(value)]
+			return ok
+		}
+		buf[i] = lowerAscii(value[i])
+	}
+	_, ok := s.values[string(buf[:len(value)])]
+	return ok
+}
@@ -0,0 +1,51 @@
+package filter
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/voronelf/logview/core"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestInSet_Match(t *testing.T) {
+	values := []string{"Alice", "bob", "42", "2.0", "9007199254740993", "true", "null"}
+	cases := []struct {
+		value         interface{}
+		caseSensitive bool
+		expected      bool
+	}{
+		0:  {value: "alice", expected: true},
+		1:  {value: "BOB", expected: true},
+		2:  {value: "carol", expected: false},
+		3:  {value: "alice", caseSensitive: true, expected: false},
+		4:  {value: "Alice", caseSensitive: true, expected: true},
+		5:  {value: json.Number("42"), expected: true},
+		6:  {value: json.Number("42.0"), expected: true},
+		7:  {value: json.Number("2"), expected: true},
+		8:  {value: float64(2), expected: true},
+		9:  {value: float64(43), expected: false},
+		10: {value: json.Number("9007199254740993"), expected: true},
+		11: {value: json.Number("9007199254740992"), expected: false},
+		12: {value: true, expected: true},
+		13: {value: false, expected: false},
+		14: {value: nil, expected: true},
+		15: {value: []interface{}{"carol", "Bob"}, expected: true},
+		16: {value: map[string]interface{}{"name": "bob"}, expected: false},
+		17: {value: strings.Repeat("Б", 100), expected: false},
+	}
+	for i, cs := range cases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			filter := NewInSet(NewField("user", cs.caseSensitive, false), values)
+			assert.Equal(t, cs.expected, filter.Match(core.Row{Data: map[string]interface{}{"user": cs.value}}))
+		})
+	}
+}
+
+func TestInSet_Match_LongValue(t *testing.T) {
+	long := strings.Repeat("x", maxFoldBuffer+10)
+	filter := NewInSet(NewField("user", false, false), []string{long, "Значение"})
+	assert.True(t, filter.Match(core.Row{Data: map[string]interface{}{"user": strings.ToUpper(long)}}))
+	assert.True(t, filter.Match(core.Row{Data: map[string]interface{}{"user": "ЗНАЧЕНИЕ"}}))
+}
@@ -0,0 +1,63 @@
+package filter
+
+import (
+	"github.com/voronelf/logview/core"
+	"strings"
+)
+
+// NewText creates filter which searches substring in values of all fields and, optionally, in field names.
+// Nested objects and arrays are searched too.
+func NewText(text string, searchFieldNames, caseSensitive bool) *textSearch {
+	return &textSearch{
+		text:             text,
+		searchFieldNames: searchFieldNames,
+		caseSensitive:    caseSensitive,
+	}
+}
+
+type textSearch struct {
+	text             string
+	searchFieldNames bool
+	caseSensitive    bool
+}
+
+var _ core.Filter = (*textSearch)(nil)
+
+func (t *textSearch) Match(row core.Row) bool {
+	return t.matchObject(row.Data)
+}
+
+func (t *textSearch) matchObject(data map[string]interface{}) bool {
+	for field, value := range data {
+		if t.searchFieldNames && t.contains(field) {
+			return true
+		}
+		if t.matchValue(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *textSearch) matchValue(value interface{}) bool {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return t.matchObject(v)
+	case []interface{}:
+		for _, element := range v {
+			if t.matchValue(element) {
+				return true
+			}
+		}
+		return false
+	default:
+		return t.contains(toString(value))
+	}
+}
+
+func (t *textSearch) contains(s string) bool {
+	if t.caseSensitive {
+		return strings.Contains(s, t.text)
+	}
+	return indexFold(s, t.text) >= 0
+}
@@ -0,0 +1,55 @@
+package filter
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/voronelf/logview/core"
+	"strconv"
+	"testing"
+)
+
+func TestText_Match(t *testing.T) {
+	row := core.Row{Data: map[string]interface{}{
+		"message":  "connection reset by peer",
+		"level":    "error",
+		"duration": "1500",
+		"tags":     []interface{}{"db", map[string]interface{}{"host": "replica"}},
+	}}
+	cases := []struct {
+		text             string
+		searchFieldNames bool
+		expected         bool
+	}{
+		{"connection reset", false, true},
+		{"reset", false, true},
+		{"50", false, true},
+		{"timeout", false, false},
+		{"durat", false, false},
+		{"durat", true, true},
+		{"rror", true, true},
+		{"", false, true},
+		{"replica", false, true},
+		{"host", false, false},
+		{"host", true, true},
+		{"CONNECTION Reset", false, true},
+		{"REPLICA", false, true},
+	}
+	for k, cs := range cases {
+		t.Run(strconv.Itoa(k), func(t *testing.T) {
+			f := NewText(cs.text, cs.searchFieldNames, false)
+			assert.Equal(t, cs.expected, f.Match(row))
+		})
+	}
+}
+
+func TestText_Match_CaseSensitive(t *testing.T) {
+	row := core.Row{Data: map[string]interface{}{"Message": "Connection reset"}}
+	assert.True(t, NewText("Connection", false, true).Match(row))
+	assert.False(t, NewText("connection", false, true).Match(row))
+	assert.True(t, NewText("Mess", true, true).Match(row))
+	assert.False(t, NewText("mess", true, true).Match(row))
+}
+
+func TestText_Match_EmptyRow(t *testing.T) {
+	f := NewText("some", true, false)
+	assert.False(t, f.Match(core.Row{Data: map[string]interface{}{}}))
+}
@@ -0,0 +1,173 @@
+package filter
+
+import (
+	"github.com/voronelf/logview/core"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timeLayouts are tried for detection of time in string values
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"02/Jan/2006:15:04:05 -0700",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC822Z,
+	time.RFC822,
+	time.UnixDate,
+	time.ANSIC,
+}
+
+// timeNow returns current time, it is replaced in tests
+var timeNow = time.Now
+
+// NewTimeCompare creates filter which compares time in field value with bound.
+// Time in field value can be string in one of common layouts or unix timestamp in seconds or milliseconds.
+// Location is used for times without zone.
+func NewTimeCompare(field Field, operation string, bound TimeBound, location *time.Location) *timeCompare {
+	return &timeCompare{
+		field:     field,
+		operation: operation,
+		bounds:    []TimeBound{bound},
+		location:  location,
+	}
+}
+
+// NewTimeBetween creates filter which matches time in field value between from and to inclusive
+func NewTimeBetween(field Field, from, to TimeBound, location *time.Location) *timeCompare {
+	return &timeCompare{
+		field:     field,
+		operation: opBetween,
+		bounds:    []TimeBound{from, to},
+		location:  location,
+	}
+}
+
+type timeCompare struct {
+	field     Field
+	operation string
+	bounds    []TimeBound
+	location  *time.Location
+}
+
+var _ core.Filter = (*timeCompare)(nil)
+
+func (c *timeCompare) Match(row core.Row) bool {
+	return matchField(row.Data, c.field, c.matchRowValue)
+}
+
+func (c *timeCompare) matchRowValue(rowValue interface{}) bool {
+	parser := timeParser{}
+	rowTime, ok := parser.parse(rowValue, c.location)
+	if !ok {
+		return false
+	}
+	now := timeNow()
+	if c.operation == opBetween {
+		return !rowTime.Before(c.bounds[0].at(now)) && !rowTime.After(c.bounds[1].at(now))
+	}
+	bound := c.bounds[0].at(now)
+	return matchComparison(c.operation, compareOrdered(rowTime.Before(bound), rowTime.After(bound)))
+}
+
+// TimeBound is absolute time or time relative to current moment like 'now-15m'
+type TimeBound struct {
+	relative bool
+	offset   time.Duration
+	absolute time.Time
+}
+
+func (b TimeBound) at(now time.Time) time.Time {
+	if b.relative {
+		return now.Add(b.offset)
+	}
+	return b.absolute
+}
+
+// ParseTimeBound parses 'now', 'now-15m', 'now+1h', 'now-2d' or time in one of common layouts.
+// Numbers are not treated as time bound. Location is used for times without zone.
+func ParseTimeBound(value string, location *time.Location) (TimeBound, bool) {
+	lower := strings.ToLower(value)
+	if strings.HasPrefix(lower, "now") {
+		offset, ok := parseOffset(lower[len("now"):])
+		return TimeBound{relative: true, offset: offset}, ok
+	}
+	parser := timeParser{}
+	absolute, ok := parser.parseString(value, location)
+	return TimeBound{absolute: absolute}, ok
+}
+
+// parseOffset parses offset like '-15m' or '+2d', days are supported besides units of time.ParseDuration
+func parseOffset(offset string) (time.Duration, bool) {
+	if offset == "" {
+		return 0, true
+	}
+	if offset[0] != '-' && offset[0] != '+' {
+		return 0, false
+	}
+	if strings.HasSuffix(offset, "d") {
+		days, err := strconv.Atoi(offset[:len(offset)-1])
+		return time.Duration(days) * 24 * time.Hour, err == nil
+	}
+	duration, err := time.ParseDuration(offset)
+	return duration, err == nil
+}
+
+// timeParser detects time in values, last matched layout is tried first.
+// Parser isn't safe for concurrent use, so filters create it for every value.
+type timeParser struct {
+	lastLayout int
+}
+
+func (p *timeParser) parse(value interface{}, location *time.Location) (time.Time, bool) {
+	if str, ok := value.(string); ok {
+		if t, ok := p.parseString(str, location); ok {
+			return t, true
+		}
+	}
+	if n, ok := toNumber(value); ok {
+		return unixTime(n.floatValue), true
+	}
+	return time.Time{}, false
+}
+
+func (p *timeParser) parseString(value string, location *time.Location) (time.Time, bool) {
+	// layouts contain at least date, so short values and numbers are skipped
+	if len(value) < len("2006-01-02") {
+		return time.Time{}, false
+	}
+	if _, isNumber := parseNumber(value); isNumber {
+		return time.Time{}, false
+	}
+	for i := range timeLayouts {
+		k := (p.lastLayout + i) % len(timeLayouts)
+		t, err := time.ParseInLocation(timeLayouts[k], value, location)
+		if err == nil {
+			p.lastLayout = k
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// unixTime converts unix timestamp to time, magnitude of timestamp detects seconds, milliseconds,
+// microseconds or nanoseconds
+func unixTime(timestamp float64) time.Time {
+	switch {
+	case timestamp < 1e11:
+		return time.Unix(0, int64(timestamp*1e9))
+	case timestamp < 1e14:
+		return time.Unix(0, int64(timestamp*1e6))
+	case timestamp < 1e17:
+		return time.Unix(0, int64(timestamp*1e3))
+	default:
+		return time.Unix(0, int64(timestamp))
+	}
+}
@@ -0,0 +1,113 @@
+package filter
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/voronelf/logview/core"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func stubTimeNow(now time.Time) func() {
+	timeNow = func() time.Time { return now }
+	return func() { timeNow = time.Now }
+}
+
+func TestParseTimeBound(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	moscow := time.FixedZone("MSK", 3*3600)
+	cases := []struct {
+		value    string
+		ok       bool
+		expected time.Time
+	}{
+		{value: "now", ok: true, expected: now},
+		{value: "NOW-15m", ok: true, expected: now.Add(-15 * time.Minute)},
+		{value: "now+1h30m", ok: true, expected: now.Add(90 * time.Minute)},
+		{value: "now-2d", ok: true, expected: now.Add(-48 * time.Hour)},
+		{value: "now15m", ok: false},
+		{value: "now-xd", ok: false},
+		{value: "nowhere", ok: false},
+		{value: "2024-05-01T10:05:00Z", ok: true, expected: time.Date(2024, 5, 1, 10, 5, 0, 0, time.UTC)},
+		{value: "2024-05-01T10:05:00.123+03:00", ok: true, expected: time.Date(2024, 5, 1, 7, 5, 0, 123e6, time.UTC)},
+		{value: "2024-05-01T10:05", ok: true, expected: time.Date(2024, 5, 1, 10, 5, 0, 0, moscow)},
+		{value: "2024-05-01 10:05:01", ok: true, expected: time.Date(2024, 5, 1, 10, 5, 1, 0, moscow)},
+		{value: "2024-05-01", ok: true, expected: time.Date(2024, 5, 1, 0, 0, 0, 0, moscow)},
+		{value: "01/May/2024:10:05:00 +0000", ok: true, expected: time.Date(2024, 5, 1, 10, 5, 0, 0, time.UTC)},
+		{value: "1714557900", ok: false},
+		{value: "someString", ok: false},
+	}
+	for k, cs := range cases {
+		t.Run(strconv.Itoa(k), func(t *testing.T) {
+			bound, ok := ParseTimeBound(cs.value, moscow)
+			assert.Equal(t, cs.ok, ok)
+			if cs.ok {
+				assert.True(t, cs.expected.Equal(bound.at(now)), "expected %s, actual %s", cs.expected, bound.at(now))
+			}
+		})
+	}
+}
+
+func TestUnixTime(t *testing.T) {
+	expected := time.Date(2024, 5, 1, 10, 5, 0, 0, time.UTC)
+	assert.True(t, expected.Equal(unixTime(1714557900)))
+	assert.True(t, expected.Equal(unixTime(1714557900000)))
+	assert.True(t, expected.Equal(unixTime(1714557900000000)))
+	assert.True(t, expected.Equal(unixTime(1714557900000000000)))
+	assert.True(t, expected.Add(500*time.Millisecond).Equal(unixTime(1714557900.5)))
+}
+
+func TestTimeCompare_Match(t *testing.T) {
+	defer stubTimeNow(time.Date(2024, 5, 1, 10, 10, 0, 0, time.UTC))()
+	row := core.Row{Data: map[string]interface{}{
+		"time":    "2024-05-01T10:05:00Z",
+		"ts":      json.Number("1714557900123"),
+		"local":   "2024-05-01 13:05:00",
+		"float":   float64(1714557900),
+		"invalid": "someString",
+	}}
+	msk := time.FixedZone("MSK", 3*3600)
+	bound := func(value string) TimeBound {
+		b, ok := ParseTimeBound(value, time.UTC)
+		if !ok {
+			t.Fatal("invalid bound " + value)
+		}
+		return b
+	}
+	cases := []struct {
+		field     string
+		operation string
+		bound     string
+		expected  bool
+	}{
+		{field: "time", operation: ">", bound: "now-15m", expected: true},
+		{field: "time", operation: ">", bound: "now-5m", expected: false},
+		{field: "time", operation: "<=", bound: "now", expected: true},
+		{field: "time", operation: "=", bound: "2024-05-01T10:05:00Z", expected: true},
+		{field: "time", operation: "!=", bound: "2024-05-01T10:05:00Z", expected: false},
+		{field: "ts", operation: ">", bound: "2024-05-01T10:05:00Z", expected: true},
+		{field: "ts", operation: "<", bound: "now-1m", expected: true},
+		{field: "local", operation: "=", bound: "2024-05-01T10:05:00Z", expected: true},
+		{field: "float", operation: ">=", bound: "2024-05-01T10:05:00Z", expected: true},
+		{field: "invalid", operation: "<", bound: "now", expected: false},
+		{field: "invalid", operation: "!=", bound: "now", expected: false},
+		{field: "wrong", operation: "<", bound: "now", expected: false},
+	}
+	for k, cs := range cases {
+		t.Run(strconv.Itoa(k), func(t *testing.T) {
+			f := NewTimeCompare(NewField(cs.field, false, false), cs.operation, bound(cs.bound), msk)
+			assert.Equal(t, cs.expected, f.Match(row))
+		})
+	}
+}
+
+func TestTimeBetween_Match(t *testing.T) {
+	defer stubTimeNow(time.Date(2024, 5, 1, 10, 10, 0, 0, time.UTC))()
+	row := core.Row{Data: map[string]interface{}{"time": "2024-05-01T10:05:00Z"}}
+	from, _ := ParseTimeBound("2024-05-01T10:00", time.UTC)
+	to, _ := ParseTimeBound("2024-05-01T10:05", time.UTC)
+	assert.True(t, NewTimeBetween(NewField("time", false, false), from, to, time.UTC).Match(row))
+	to, _ = ParseTimeBound("now-6m", time.UTC)
+	assert.False(t, NewTimeBetween(NewField("time", false, false), from, to, time.UTC).Match(row))
+}
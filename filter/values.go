@@ -0,0 +1,139 @@
+package filter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// toString converts scalar value of row to string, strings and numbers from json are converted without allocations
+func toString(val interface{}) string {
+	switch v := val.(type) {
+	case nil:
+		return "null"
+	case string:
+		return v
+	case json.Number:
+		return string(v)
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// number is parsed value, integers are kept without loss of precision
+type number struct {
+	isInt      bool
+	intValue   int64
+	floatValue float64
+}
+
+// parseNumber parses string as number, strings which can't be number are rejected without allocations
+func parseNumber(s string) (number, bool) {
+	if !looksLikeNumber(s) {
+		return number{}, false
+	}
+	if !strings.ContainsAny(s, ".eE") {
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return number{isInt: true, intValue: i, floatValue: float64(i)}, true
+		}
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return number{floatValue: f}, true
+	}
+	return number{}, false
+}
+
+func looksLikeNumber(s string) bool {
+	hasDigit := false
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c >= '0' && c <= '9':
+			hasDigit = true
+		case c == '-' || c == '+' || c == '.' || c == 'e' || c == 'E':
+		default:
+			return false
+		}
+	}
+	return hasDigit
+}
+
+// toNumber converts value of row to number
+func toNumber(val interface{}) (number, bool) {
+	switch v := val.(type) {
+	case float64:
+		return number{floatValue: v}, true
+	case json.Number:
+		return parseNumber(string(v))
+	case string:
+		return parseNumber(v)
+	default:
+		return number{}, false
+	}
+}
+
+func compareNumbers(a, b number) int {
+	if a.isInt && b.isInt {
+		return compareOrdered(a.intValue < b.intValue, a.intValue > b.intValue)
+	}
+	return compareOrdered(a.floatValue < b.floatValue, a.floatValue > b.floatValue)
+}
+
+func compareOrdered(less, greater bool) int {
+	switch {
+	case less:
+		return -1
+	case greater:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// compareFold compares strings lexically like strings.Compare for lower-cased strings, but without allocations
+func compareFold(a, b string) int {
+	for a != "" && b != "" {
+		runeA, sizeA := utf8.DecodeRuneInString(a)
+		runeB, sizeB := utf8.DecodeRuneInString(b)
+		lowerA, lowerB := unicode.ToLower(runeA), unicode.ToLower(runeB)
+		if lowerA != lowerB {
+			return compareOrdered(lowerA < lowerB, lowerA > lowerB)
+		}
+		a, b = a[sizeA:], b[sizeB:]
+	}
+	return compareOrdered(a == "" && b != "", a != "" && b == "")
+}
+
+// indexFold returns index of first case insensitive occurrence of substr in s, or -1
+func indexFold(s, substr string) int {
+	n := len(substr)
+	if n == 0 {
+		return 0
+	}
+	first := lowerAscii(substr[0])
+	for i := 0; i+n <= len(s); i++ {
+		if first < utf8.RuneSelf && lowerAscii(s[i]) != first {
+			continue
+		}
+		if strings.EqualFold(s[i:i+n], substr) {
+			return i
+		}
+	}
+	return -1
+}
+
+func lowerAscii(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
@@ -0,0 +1,59 @@
+package filter
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+)
+
+func TestToString(t *testing.T) {
+	assert.Equal(t, "null", toString(nil))
+	assert.Equal(t, "value", toString("value"))
+	assert.Equal(t, "1234567890123456789", toString(json.Number("1234567890123456789")))
+	assert.Equal(t, "123.45", toString(float64(123.45)))
+	assert.Equal(t, "true", toString(true))
+	assert.Equal(t, "[a b]", toString([]interface{}{"a", "b"}))
+}
+
+func TestToNumber(t *testing.T) {
+	cases := []struct {
+		value    interface{}
+		ok       bool
+		expected number
+	}{
+		{value: "123", ok: true, expected: number{isInt: true, intValue: 123, floatValue: 123}},
+		{value: json.Number("-5"), ok: true, expected: number{isInt: true, intValue: -5, floatValue: -5}},
+		{value: "56.78", ok: true, expected: number{floatValue: 56.78}},
+		{value: "1e3", ok: true, expected: number{floatValue: 1000}},
+		{value: float64(2.5), ok: true, expected: number{floatValue: 2.5}},
+		{value: "abc", ok: false},
+		{value: "-", ok: false},
+		{value: "1-2", ok: false},
+		{value: true, ok: false},
+		{value: nil, ok: false},
+	}
+	for k, cs := range cases {
+		t.Run(strconv.Itoa(k), func(t *testing.T) {
+			actual, ok := toNumber(cs.value)
+			assert.Equal(t, cs.ok, ok)
+			assert.Equal(t, cs.expected, actual)
+		})
+	}
+}
+
+func TestCompareFold(t *testing.T) {
+	assert.Equal(t, 0, compareFold("SomeString", "somestring"))
+	assert.Equal(t, -1, compareFold("abc", "ABD"))
+	assert.Equal(t, 1, compareFold("Abc", "ab"))
+	assert.Equal(t, -1, compareFold("", "a"))
+	assert.Equal(t, 0, compareFold("Привет", "пРИВЕТ"))
+}
+
+func TestIndexFold(t *testing.T) {
+	assert.Equal(t, 0, indexFold("abc", ""))
+	assert.Equal(t, 11, indexFold("Connection Reset", "reset"))
+	assert.Equal(t, -1, indexFold("Connection", "refused"))
+	assert.Equal(t, -1, indexFold("ab", "abc"))
+	assert.Equal(t, 11, indexFold("скажи ПРИВЕТ", "привет"))
+}
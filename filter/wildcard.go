@@ -1,57 +1,48 @@
 package filter
 
 import (
-	wildcardPkg "github.com/ryanuber/go-glob"
 	"github.com/voronelf/logview/core"
 	"strings"
 )
 
-func NewWildcard(field, value string) *wildcard {
-	return &wildcard{
-		field:           field,
-		fieldIsWildcard: strings.Contains(field, "*"),
-		values:          strings.Split(value, "|"),
+// NewWildcard creates filter which matches field value by patterns divided '|'.
+// Pattern can contain '*' and can be negative, starts from '!'. Values are compared with case sensitivity of field.
+func NewWildcard(field Field, value string) *wildcard {
+	w := &wildcard{field: field}
+	for _, val := range strings.Split(value, "|") {
+		negative := strings.HasPrefix(val, "!")
+		if negative {
+			val = val[1:]
+		}
+		w.patterns = append(w.patterns, wildcardPattern{
+			glob:     compileGlob(val, field.caseSensitive),
+			negative: negative,
+		})
 	}
+	return w
 }
 
 type wildcard struct {
-	field           string
-	fieldIsWildcard bool
-	values          []string
+	field    Field
+	patterns []wildcardPattern
+}
+
+type wildcardPattern struct {
+	glob     globPattern
+	negative bool
 }
 
 var _ core.Filter = (*wildcard)(nil)
 
 func (w *wildcard) Match(row core.Row) bool {
-	if w.fieldIsWildcard {
-		for rowField, rowValue := range row.Data {
-			if !wildcardPkg.Glob(w.field, rowField) {
-				continue
-			}
-			if w.matchRowValue(rowValue) {
-				return true
-			}
-		}
-	} else {
-		rowValue, ok := row.Data[w.field]
-		if ok && w.matchRowValue(rowValue) {
-			return true
-		}
-	}
-	return false
+	return matchField(row.Data, w.field, w.matchRowValue)
 }
 
 func (w *wildcard) matchRowValue(rowValue interface{}) bool {
 	rowValueString := toString(rowValue)
-	for _, val := range w.values {
-		if val[0] != '!' {
-			if wildcardPkg.Glob(val, rowValueString) {
-				return true
-			}
-		} else {
-			if !wildcardPkg.Glob(val[1:], rowValueString) {
-				return true
-			}
+	for _, pattern := range w.patterns {
+		if pattern.glob.match(rowValueString) != pattern.negative {
+			return true
 		}
 	}
 	return false
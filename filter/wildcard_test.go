@@ -15,7 +15,7 @@ func TestWildcard_Match(t *testing.T) {
 		{field: "strField", val: "someString", expected: true},
 		{field: "strField", val: "other|someString|wrong", expected: true},
 		{field: "strField", val: "otherString", expected: false},
-		{field: "strField", val: "SomeStRinG", expected: false},
+		{field: "strField", val: "SomeStRinG", expected: true},
 		{field: "strField", val: "some", expected: false},
 		{field: "strField", val: "*", expected: true},
 		{field: "strField", val: "some*", expected: true},
@@ -42,9 +42,19 @@ func TestWildcard_Match(t *testing.T) {
 	}
 	for k, cs := range cases {
 		t.Run(strconv.Itoa(k), func(t *testing.T) {
-			f := NewWildcard(cs.field, cs.val)
+			f := NewWildcard(NewField(cs.field, false, false), cs.val)
 			assert.Equal(t, cs.expected, f.Match(getRow()))
 		})
 	}
 
 }
+
+func TestWildcard_Match_CaseSensitive(t *testing.T) {
+	assert.True(t, NewWildcard(NewField("strField", true, false), "some*").Match(getRow()))
+	assert.False(t, NewWildcard(NewField("strfield", true, false), "some*").Match(getRow()))
+	assert.True(t, NewWildcard(NewField("str*", true, false), "*String").Match(getRow()))
+	assert.False(t, NewWildcard(NewField("STR*", true, false), "*").Match(getRow()))
+	assert.True(t, NewWildcard(NewField("STR*", false, false), "*").Match(getRow()))
+	assert.False(t, NewWildcard(NewField("strField", true, false), "SomeString").Match(getRow()))
+	assert.True(t, NewWildcard(NewField("strField", true, false), "some*|!Some*").Match(getRow()))
+}
@@ -1,11 +1,14 @@
 package formatter
 
 import (
+	"fmt"
 	"github.com/fatih/color"
 	wildcardPkg "github.com/ryanuber/go-glob"
 	"github.com/voronelf/logview/core"
+	"github.com/voronelf/logview/severity"
 	"sort"
 	"strings"
+	"sync"
 )
 
 func NewCliColor() *cliColor {
@@ -13,6 +16,10 @@ func NewCliColor() *cliColor {
 }
 
 type cliColor struct {
+	mu sync.Mutex
+	// levels is normalizer of levelFields, it is rebuilt only when level fields are changed
+	levels      *severity.Normalizer
+	levelFields []string
 }
 
 var _ core.Formatter = (*cliColor)(nil)
@@ -27,11 +34,27 @@ func (s *cliColor) Format(row core.Row, params core.FormatParams) string {
 	clrError := color.New(color.FgRed)
 
 	divider := clrAround.Sprint("**********")
-	text := divider + " " + s.formatHeader(row) + " " + divider + "\n"
+	text := divider + " "
+	if row.Source != "" {
+		text += clrAround.Sprint("["+row.Source+"]") + " "
+	}
+	text += s.formatHeader(row, s.normalizer(params.LevelFields)) + " "
+	if row.Repaired > 0 {
+		text += clrError.Sprintf("(%d invalid UTF-8 sequences replaced)", row.Repaired) + " "
+	}
+	text += divider + "\n"
 	if row.Err == nil {
-		fieldList := make([]string, 0, len(row.Data))
-		if len(params.OutputFields) > 0 {
+		rowFields := row.Fields
+		if !params.OriginalOrder || len(rowFields) == 0 {
+			rowFields = make([]string, 0, len(row.Data))
 			for field := range row.Data {
+				rowFields = append(rowFields, field)
+			}
+			sort.Strings(rowFields)
+		}
+		fieldList := make([]string, 0, len(rowFields))
+		if len(params.OutputFields) > 0 {
+			for _, field := range rowFields {
 				for _, wildcard := range params.OutputFields {
 					if s.isMatchWildcard(wildcard, field) {
 						fieldList = append(fieldList, field)
@@ -40,11 +63,8 @@ func (s *cliColor) Format(row core.Row, params core.FormatParams) string {
 				}
 			}
 		} else {
-			for field := range row.Data {
-				fieldList = append(fieldList, field)
-			}
+			fieldList = rowFields
 		}
-		sort.Strings(fieldList)
 		for _, field := range fieldList {
 			value, ok := row.Data[field]
 			if !ok {
@@ -84,23 +104,56 @@ func (*cliColor) isMatchWildcard(wildcard, value string) bool {
 	}
 }
 
-func (*cliColor) formatHeader(row core.Row) string {
-	var c *color.Color
-	level, ok := row.Data["level"].(string)
+// normalizer returns normalizer of level fields, it is created once for same level fields
+func (s *cliColor) normalizer(levelFields []string) *severity.Normalizer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.levels == nil || !equalStrings(s.levelFields, levelFields) {
+		s.levels = severity.NewNormalizer(levelFields)
+		s.levelFields = levelFields
+	}
+	return s.levels
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// formatHeader shows severity level of row, color depends on normalized level
+func (*cliColor) formatHeader(row core.Row, levels *severity.Normalizer) string {
+	value, level, ok := levels.Find(row.Data)
 	if !ok {
 		return "No level field"
 	}
+	text, isString := value.(string)
+	if !isString {
+		text = fmt.Sprint(value)
+		if level != severity.Unknown {
+			text = level.String() + " (" + text + ")"
+		}
+	}
+	var c *color.Color
 	switch level {
-	case "debug":
+	case severity.Trace, severity.Debug:
 		c = color.New(color.FgBlack, color.BgCyan)
-	case "info":
+	case severity.Info:
 		c = color.New(color.FgBlack, color.BgGreen)
-	case "warn", "warning":
+	case severity.Warn:
 		c = color.New(color.FgBlack, color.BgHiYellow)
-	case "error":
+	case severity.Error:
 		c = color.New(color.FgBlack, color.BgRed)
+	case severity.Fatal:
+		c = color.New(color.FgHiWhite, color.BgHiRed)
 	default:
 		c = color.New(color.FgBlack, color.BgMagenta)
 	}
-	return c.Sprint("  Level: " + level + "  ")
+	return c.Sprint("  Level: " + text + "  ")
 }
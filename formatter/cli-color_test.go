@@ -2,6 +2,8 @@ package formatter
 
 import (
 	"github.com/stretchr/testify/assert"
+	"github.com/voronelf/logview/core"
+	"github.com/voronelf/logview/severity"
 	"strconv"
 	"testing"
 )
@@ -32,3 +34,45 @@ func TestCliColor_isMatchWildcard(t *testing.T) {
 	}
 
 }
+
+func TestCliColor_Format_FieldsOrder(t *testing.T) {
+	row := core.Row{
+		Data:   map[string]interface{}{"time": "now", "message": "text", "id": 1},
+		Fields: []string{"time", "message", "id"},
+	}
+	params := core.DefaultFormatParams()
+	sorted := NewCliColor().Format(row, params)
+	assert.Regexp(t, "(?s)id: 1.*message: text.*time: now", sorted)
+
+	params.OriginalOrder = true
+	original := NewCliColor().Format(row, params)
+	assert.Regexp(t, "(?s)time: now.*message: text.*id: 1", original)
+
+	params.OutputFields = []string{"id", "time"}
+	filtered := NewCliColor().Format(row, params)
+	assert.Regexp(t, "(?s)time: now.*id: 1", filtered)
+	assert.NotContains(t, filtered, "message")
+}
+
+func TestCliColor_formatHeader(t *testing.T) {
+	cases := []struct {
+		data        map[string]interface{}
+		levelFields []string
+		expected    string
+	}{
+		{map[string]interface{}{"level": "error"}, nil, "  Level: error  "},
+		{map[string]interface{}{"lvl": "WARN"}, nil, "  Level: WARN  "},
+		{map[string]interface{}{"level": float64(30)}, nil, "  Level: info (30)  "},
+		{map[string]interface{}{"severity": "CRITICAL"}, nil, "  Level: CRITICAL  "},
+		{map[string]interface{}{"level": float64(99.5)}, nil, "  Level: fatal (99.5)  "},
+		{map[string]interface{}{"level": true}, nil, "  Level: true  "},
+		{map[string]interface{}{"message": "text"}, nil, "No level field"},
+		{map[string]interface{}{"level": "info", "sev": "debug"}, []string{"sev"}, "  Level: debug  "},
+	}
+	for i, cs := range cases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			actual := NewCliColor().formatHeader(core.Row{Data: cs.data}, severity.NewNormalizer(cs.levelFields))
+			assert.Equal(t, cs.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package merge
+
+import (
+	"container/heap"
+	"context"
+	"encoding/json"
+	"github.com/voronelf/logview/core"
+	"strconv"
+	"time"
+)
+
+type Params struct {
+	TimeField  string
+	TimeLayout string
+	Window     time.Duration
+}
+
+func DefaultParams() Params {
+	return Params{
+		TimeField:  "time",
+		TimeLayout: time.RFC3339,
+		Window:     time.Second,
+	}
+}
+
+// ByTime interleaves rows from several sources in chronological order of the time field.
+// Every source is expected to be roughly ordered by itself: a row is released only when all opened
+// sources have reached its time plus the reorder window. Every row is labelled with its source name.
+func ByTime(ctx context.Context, sources map[string]<-chan core.Row, params Params) <-chan core.Row {
+	outputCh := make(chan core.Row, 16)
+	incomingCh := make(chan sourceRow, 16)
+	for name, ch := range sources {
+		go readSource(ctx, name, ch, incomingCh)
+	}
+	go func() {
+		defer close(outputCh)
+		m := newMerger(params, sources)
+		for m.openCount > 0 {
+			select {
+			case in := <-incomingCh:
+				if in.closed {
+					m.closeSource(in.source)
+				} else if in.row.Err != nil {
+					in.row.Source = in.source
+					if !send(ctx, outputCh, in.row) {
+						return
+					}
+					continue
+				} else {
+					m.push(in.source, in.row)
+				}
+				for _, row := range m.popReleased() {
+					if !send(ctx, outputCh, row) {
+						return
+					}
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+		for m.queue.Len() > 0 {
+			if !send(ctx, outputCh, heap.Pop(&m.queue).(queueItem).row) {
+				return
+			}
+		}
+	}()
+	return outputCh
+}
+
+type sourceRow struct {
+	source string
+	row    core.Row
+	closed bool
+}
+
+func readSource(ctx context.Context, name string, ch <-chan core.Row, incomingCh chan<- sourceRow) {
+	for {
+		select {
+		case row, ok := <-ch:
+			if !ok {
+				select {
+				case incomingCh <- sourceRow{source: name, closed: true}:
+				case <-ctx.Done():
+				}
+				return
+			}
+			select {
+			case incomingCh <- sourceRow{source: name, row: row}:
+			case <-ctx.Done():
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func send(ctx context.Context, outputCh chan<- core.Row, row core.Row) bool {
+	select {
+	case outputCh <- row:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+type sourceState struct {
+	opened   bool
+	hasTime  bool
+	lastTime time.Time
+}
+
+func newMerger(params Params, sources map[string]<-chan core.Row) *merger {
+	m := &merger{
+		params:    params,
+		states:    make(map[string]*sourceState, len(sources)),
+		openCount: len(sources),
+	}
+	for name := range sources {
+		m.states[name] = &sourceState{opened: true}
+	}
+	return m
+}
+
+type merger struct {
+	params    Params
+	states    map[string]*sourceState
+	openCount int
+	queue     rowsQueue
+	seq       int64
+}
+
+func (m *merger) push(source string, row core.Row) {
+	row.Source = source
+	state := m.states[source]
+	t, ok := ParseTime(row.Data[m.params.TimeField], m.params.TimeLayout)
+	if ok {
+		if !state.hasTime || t.After(state.lastTime) {
+			state.lastTime = t
+		}
+		state.hasTime = true
+	} else {
+		// keep rows without time near to previous row of the same source
+		t = state.lastTime
+	}
+	heap.Push(&m.queue, queueItem{row: row, time: t, seq: m.seq})
+	m.seq++
+}
+
+func (m *merger) closeSource(source string) {
+	state := m.states[source]
+	if state.opened {
+		state.opened = false
+		m.openCount--
+	}
+}
+
+// popReleased returns rows which can't be preceded by any row not read yet
+func (m *merger) popReleased() []core.Row {
+	if m.openCount == 0 {
+		return nil
+	}
+	var watermark time.Time
+	first := true
+	for _, state := range m.states {
+		if !state.opened {
+			continue
+		}
+		if !state.hasTime {
+			return nil
+		}
+		if first || state.lastTime.Before(watermark) {
+			watermark = state.lastTime
+			first = false
+		}
+	}
+	watermark = watermark.Add(-m.params.Window)
+	var rows []core.Row
+	for m.queue.Len() > 0 && !m.queue[0].time.After(watermark) {
+		rows = append(rows, heap.Pop(&m.queue).(queueItem).row)
+	}
+	return rows
+}
+
+// ParseTime converts value of time field to time.Time. Numbers are treated as unix timestamps in seconds.
+func ParseTime(value interface{}, layout string) (time.Time, bool) {
+	switch v := value.(type) {
+	case string:
+		t, err := time.Parse(layout, v)
+		if err != nil {
+			sec, e := strconv.ParseFloat(v, 64)
+			if e != nil {
+				return time.Time{}, false
+			}
+			return unixFloat(sec), true
+		}
+		return t, true
+	case float64:
+		return unixFloat(v), true
+	case json.Number:
+		sec, err := v.Float64()
+		if err != nil {
+			return time.Time{}, false
+		}
+		return unixFloat(sec), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+func unixFloat(sec float64) time.Time {
+	return time.Unix(0, int64(sec*float64(time.Second)))
+}
+
+type queueItem struct {
+	row  core.Row
+	time time.Time
+	seq  int64
+}
+
+type rowsQueue []queueItem
+
+var _ heap.Interface = (*rowsQueue)(nil)
+
+func (q rowsQueue) Len() int {
+	return len(q)
+}
+
+func (q rowsQueue) Less(i, j int) bool {
+	if q[i].time.Equal(q[j].time) {
+		return q[i].seq < q[j].seq
+	}
+	return q[i].time.Before(q[j].time)
+}
+
+func (q rowsQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *rowsQueue) Push(x interface{}) {
+	*q = append(*q, x.(queueItem))
+}
+
+func (q *rowsQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	*q = old[:n-1]
+	return item
+}
@@ -0,0 +1,125 @@
+package merge
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/voronelf/logview/core"
+	"testing"
+	"time"
+)
+
+func sourceFromRows(rows ...core.Row) <-chan core.Row {
+	ch := make(chan core.Row, len(rows))
+	for _, row := range rows {
+		ch <- row
+	}
+	close(ch)
+	return ch
+}
+
+func rowWithTime(t string, msg string) core.Row {
+	return core.Row{Data: map[string]interface{}{"time": t, "message": msg}}
+}
+
+func collect(ch <-chan core.Row) []string {
+	result := []string{}
+	for row := range ch {
+		if row.Err != nil {
+			result = append(result, row.Source+":"+row.Err.Error())
+			continue
+		}
+		result = append(result, row.Source+":"+row.Data["message"].(string))
+	}
+	return result
+}
+
+func TestByTime(t *testing.T) {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+	sources := map[string]<-chan core.Row{
+		"api": sourceFromRows(
+			rowWithTime("2017-09-28T10:00:01Z", "a1"),
+			rowWithTime("2017-09-28T10:00:04Z", "a4"),
+		),
+		"worker": sourceFromRows(
+			rowWithTime("2017-09-28T10:00:02Z", "w2"),
+			rowWithTime("2017-09-28T10:00:03Z", "w3"),
+			rowWithTime("2017-09-28T10:00:05Z", "w5"),
+		),
+		"gateway": sourceFromRows(),
+	}
+	params := DefaultParams()
+	params.Window = 0
+
+	actual := collect(ByTime(ctx, sources, params))
+	assert.Equal(t, []string{"api:a1", "worker:w2", "worker:w3", "api:a4", "worker:w5"}, actual)
+}
+
+func TestByTime_ReorderWindow(t *testing.T) {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+	api := make(chan core.Row)
+	worker := make(chan core.Row)
+	sources := map[string]<-chan core.Row{"api": api, "worker": worker}
+	params := DefaultParams()
+	params.Window = 2 * time.Second
+
+	outputCh := ByTime(ctx, sources, params)
+	api <- rowWithTime("2017-09-28T10:00:03Z", "a3")
+	worker <- rowWithTime("2017-09-28T10:00:04Z", "w4")
+	api <- rowWithTime("2017-09-28T10:00:02Z", "a2")
+	worker <- rowWithTime("2017-09-28T10:00:01Z", "w1")
+	close(api)
+	close(worker)
+
+	assert.Equal(t, []string{"worker:w1", "api:a2", "api:a3", "worker:w4"}, collect(outputCh))
+}
+
+func TestByTime_RowsWithoutTime(t *testing.T) {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+	sources := map[string]<-chan core.Row{
+		"api": sourceFromRows(
+			rowWithTime("2017-09-28T10:00:01Z", "a1"),
+			core.Row{Data: map[string]interface{}{"message": "a1-trace"}},
+			core.Row{Err: errors.New("bad row")},
+			rowWithTime("2017-09-28T10:00:03Z", "a3"),
+		),
+		"worker": sourceFromRows(
+			rowWithTime("2017-09-28T10:00:02Z", "w2"),
+		),
+	}
+
+	actual := collect(ByTime(ctx, sources, DefaultParams()))
+	assert.Len(t, actual, 5)
+	assert.Contains(t, actual, "api:bad row")
+	rows := []string{}
+	for _, v := range actual {
+		if v != "api:bad row" {
+			rows = append(rows, v)
+		}
+	}
+	assert.Equal(t, []string{"api:a1", "api:a1-trace", "worker:w2", "api:a3"}, rows)
+}
+
+func TestParseTime(t *testing.T) {
+	expected := time.Date(2017, 9, 28, 10, 0, 1, 0, time.UTC)
+	cases := []struct {
+		value interface{}
+		ok    bool
+	}{
+		{"2017-09-28T10:00:01Z", true},
+		{float64(expected.Unix()), true},
+		{"1506592801", true},
+		{"yesterday", false},
+		{nil, false},
+	}
+	for _, cs := range cases {
+		actual, ok := ParseTime(cs.value, time.RFC3339)
+		assert.Equal(t, cs.ok, ok, "%v", cs.value)
+		if cs.ok {
+			assert.True(t, expected.Equal(actual), "%v", cs.value)
+		}
+	}
+}
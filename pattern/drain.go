@@ -0,0 +1,156 @@
+package pattern
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Wildcard replaces variable tokens in templates
+const Wildcard = "<*>"
+
+type Params struct {
+	// Depth is count of first tokens, which are used for search of clusters
+	Depth int
+	// Similarity is minimal share of equal tokens of message and template for adding message to cluster
+	Similarity float64
+	// MaxChildren is maximal count of children of tree node, other tokens are treated as variables
+	MaxChildren int
+}
+
+func DefaultParams() Params {
+	return Params{
+		Depth:       1,
+		Similarity:  0.4,
+		MaxChildren: 100,
+	}
+}
+
+// Cluster is a group of similar messages with common template
+type Cluster struct {
+	ID     int
+	Tokens []string
+	Count  int
+}
+
+// Template returns tokens of cluster, variable tokens are masked by wildcard
+func (c *Cluster) Template() string {
+	return strings.Join(c.Tokens, " ")
+}
+
+// NewMiner creates miner of message templates by Drain algorithm
+func NewMiner(params Params) *Miner {
+	return &Miner{
+		params: params,
+		root:   newNode(),
+	}
+}
+
+// Miner clusters messages to templates. It puts messages to fixed depth tree by count of tokens
+// and first tokens, then compares message with templates of clusters in leaf of tree.
+type Miner struct {
+	params   Params
+	root     *node
+	clusters []*Cluster
+}
+
+type node struct {
+	children map[string]*node
+	clusters []*Cluster
+}
+
+func newNode() *node {
+	return &node{children: map[string]*node{}}
+}
+
+// Add adds message to most similar cluster or creates new cluster
+func (m *Miner) Add(message string) *Cluster {
+	tokens := tokenize(message)
+	leaf := m.leaf(tokens)
+	cluster := m.bestCluster(leaf.clusters, tokens)
+	if cluster == nil {
+		cluster = &Cluster{ID: len(m.clusters) + 1, Tokens: tokens}
+		leaf.clusters = append(leaf.clusters, cluster)
+		m.clusters = append(m.clusters, cluster)
+	} else {
+		for i, token := range tokens {
+			if cluster.Tokens[i] != token {
+				cluster.Tokens[i] = Wildcard
+			}
+		}
+	}
+	cluster.Count++
+	return cluster
+}
+
+// Clusters returns all clusters in order of creation
+func (m *Miner) Clusters() []*Cluster {
+	return m.clusters
+}
+
+// leaf finds or creates leaf of tree by count of tokens and first tokens
+func (m *Miner) leaf(tokens []string) *node {
+	current := m.child(m.root, strconv.Itoa(len(tokens)), true)
+	for i := 0; i < m.params.Depth && i < len(tokens); i++ {
+		current = m.child(current, tokens[i], false)
+	}
+	return current
+}
+
+func (m *Miner) child(parent *node, key string, unlimited bool) *node {
+	if child, ok := parent.children[key]; ok {
+		return child
+	}
+	if !unlimited && len(parent.children) >= m.params.MaxChildren {
+		key = Wildcard
+		if child, ok := parent.children[key]; ok {
+			return child
+		}
+	}
+	child := newNode()
+	parent.children[key] = child
+	return child
+}
+
+// bestCluster returns cluster with most similar template or nil, if similarity is less than required
+func (m *Miner) bestCluster(clusters []*Cluster, tokens []string) *Cluster {
+	var best *Cluster
+	bestSimilarity, bestWildcards := -1.0, 0
+	for _, cluster := range clusters {
+		similarity, wildcards := similarity(cluster.Tokens, tokens)
+		if similarity > bestSimilarity || (similarity == bestSimilarity && wildcards > bestWildcards) {
+			best, bestSimilarity, bestWildcards = cluster, similarity, wildcards
+		}
+	}
+	if best == nil || bestSimilarity < m.params.Similarity {
+		return nil
+	}
+	return best
+}
+
+// similarity returns share of equal tokens and count of wildcards in template, lengths must be equal
+func similarity(template, tokens []string) (float64, int) {
+	if len(tokens) == 0 {
+		return 1, 0
+	}
+	equal, wildcards := 0, 0
+	for i, token := range template {
+		if token == Wildcard {
+			wildcards++
+		} else if token == tokens[i] {
+			equal++
+		}
+	}
+	return float64(equal) / float64(len(tokens)), wildcards
+}
+
+// tokenize splits message by spaces, tokens with digits are masked by wildcard
+func tokenize(message string) []string {
+	tokens := strings.Fields(message)
+	for i, token := range tokens {
+		if strings.IndexFunc(token, unicode.IsDigit) >= 0 {
+			tokens[i] = Wildcard
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,75 @@
+package pattern
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+)
+
+func TestMiner_Add(t *testing.T) {
+	miner := NewMiner(DefaultParams())
+	messages := []string{
+		"connection to 10.0.0.1:5432 refused",
+		"connection to 10.0.0.2:5432 refused",
+		"user alice logged in",
+		"user bob logged in",
+		"user bob logged out",
+		"request 123 finished in 15ms",
+		"connection to db refused after retry",
+		"cache miss",
+		"",
+	}
+	for _, message := range messages {
+		miner.Add(message)
+	}
+	actual := map[string]int{}
+	for _, cluster := range miner.Clusters() {
+		actual[cluster.Template()] = cluster.Count
+	}
+	expected := map[string]int{
+		"connection to <*> refused":            2,
+		"user <*> logged <*>":                  3,
+		"request <*> finished in <*>":          1,
+		"connection to db refused after retry": 1,
+		"cache miss":                           1,
+		"":                                     1,
+	}
+	assert.Equal(t, expected, actual)
+}
+
+func TestMiner_Add_Similarity(t *testing.T) {
+	cases := []struct {
+		similarity float64
+		clusters   int
+	}{
+		{0.4, 1},
+		{0.6, 1},
+		{0.8, 1},
+		{0.9, 2},
+	}
+	for i, cs := range cases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			params := DefaultParams()
+			params.Similarity = cs.similarity
+			miner := NewMiner(params)
+			first := miner.Add("job sync started by scheduler")
+			second := miner.Add("job sync started by admin")
+			assert.Len(t, miner.Clusters(), cs.clusters)
+			assert.Equal(t, cs.clusters == 1, first == second)
+		})
+	}
+}
+
+func TestMiner_Add_MaxChildren(t *testing.T) {
+	params := DefaultParams()
+	params.MaxChildren = 2
+	miner := NewMiner(params)
+	miner.Add("alpha started")
+	miner.Add("beta started")
+	miner.Add("gamma started")
+	miner.Add("delta started")
+
+	assert.Len(t, miner.Clusters(), 3)
+	assert.Equal(t, "<*> started", miner.Clusters()[2].Template())
+	assert.Equal(t, 2, miner.Clusters()[2].Count)
+}
@@ -0,0 +1,193 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/voronelf/logview/core"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+const (
+	minRestartDelay  = time.Second
+	maxRestartDelay  = time.Minute
+	stableRunPeriod  = time.Minute
+	stopCommandDelay = 5 * time.Second
+)
+
+func (r *rowProvider) WatchCommand(ctx context.Context, commandLine string, params core.ReadParams) (<-chan core.Row, error) {
+	args, err := splitCommandLine(commandLine)
+	if err != nil {
+		return nil, err
+	}
+	encoding, err := normalizeEncoding(params.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 {
+		return nil, errors.New("command is empty")
+	}
+	outputCh := make(chan core.Row, 16)
+	go func() {
+		defer close(outputCh)
+		delay := minRestartDelay
+		for {
+			started := time.Now()
+			err := runCommand(ctx, args, encoding, outputCh)
+			if ctx.Err() != nil {
+				return
+			}
+			if time.Since(started) > stableRunPeriod {
+				delay = minRestartDelay
+			}
+			if err == nil {
+				err = errors.New("exit status 0")
+			}
+			select {
+			case outputCh <- core.Row{Data: map[string]interface{}{}, Err: fmt.Errorf("command \"%s\" stopped: %s; restart in %s", commandLine, err, delay)}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return
+			}
+			delay *= 2
+			if delay > maxRestartDelay {
+				delay = maxRestartDelay
+			}
+		}
+	}()
+	return outputCh, nil
+}
+
+// runCommand starts process and sends rows from its stdout and stderr until process exits or context is done.
+// It returns only after readers of streams are finished, so outputCh can be closed after it.
+func runCommand(ctx context.Context, args []string, encoding string, outputCh chan<- core.Row) error {
+	cmd := exec.Command(args[0], args[1:]...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+	err = cmd.Start()
+	if err != nil {
+		return err
+	}
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+	go readCommandStream(ctx, stdout, "stdout", encoding, outputCh, wg)
+	go readCommandStream(ctx, stderr, "stderr", encoding, outputCh, wg)
+	doneCh := make(chan error, 1)
+	go func() {
+		wg.Wait()
+		doneCh <- cmd.Wait()
+	}()
+	select {
+	case err = <-doneCh:
+		return err
+	case <-ctx.Done():
+		stopProcess(cmd.Process, doneCh, stdout, stderr)
+		return ctx.Err()
+	}
+}
+
+// stopProcess asks process to interrupt and kills it if it is still alive after delay.
+// Streams are closed, if children of killed process still hold them, so readers are always finished.
+func stopProcess(process *os.Process, doneCh <-chan error, streams ...io.Closer) {
+	if process.Signal(os.Interrupt) == nil {
+		select {
+		case <-doneCh:
+			return
+		case <-time.After(stopCommandDelay):
+		}
+	}
+	process.Kill()
+	select {
+	case <-doneCh:
+		return
+	case <-time.After(stopCommandDelay):
+	}
+	for _, stream := range streams {
+		stream.Close()
+	}
+	<-doneCh
+}
+
+func readCommandStream(ctx context.Context, stream io.Reader, source, encoding string, outputCh chan<- core.Row, wg *sync.WaitGroup) {
+	defer wg.Done()
+	rowsCh := make(chan core.Row)
+	go func() {
+		decodedStream, _ := newDecodingReader(stream, encoding)
+		readUntilEOF(ctx, bufio.NewReaderSize(decodedStream, maxBytesInRow), rowsCh)
+		close(rowsCh)
+	}()
+	for row := range rowsCh {
+		row.Source = source
+		select {
+		case outputCh <- row:
+		case <-ctx.Done():
+			// rows are dropped after cancel, but stream is read until process is stopped
+		}
+	}
+	// drain the rest of stream, else process can be blocked on write
+	io.Copy(ioutil.Discard, stream)
+}
+
+// splitCommandLine splits command line to arguments, supports quotes and escaping by backslash
+func splitCommandLine(commandLine string) ([]string, error) {
+	args := []string{}
+	var arg bytes.Buffer
+	inArg := false
+	var quote rune
+	escaped := false
+	for _, ch := range commandLine {
+		switch {
+		case escaped:
+			arg.WriteRune(ch)
+			escaped = false
+		case ch == '\\' && quote != '\'':
+			escaped = true
+			inArg = true
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			} else {
+				arg.WriteRune(ch)
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+			inArg = true
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(ch)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, errors.New("unclosed quote in command: " + commandLine)
+	}
+	if escaped {
+		return nil, errors.New("unfinished escape sequence in command: " + commandLine)
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,104 @@
+package provider
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/voronelf/logview/core"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRowProvider_WatchCommand(t *testing.T) {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+
+	rowsChan, err := NewRowProvider().WatchCommand(ctx, `sh -c 'echo "{\"field\": \"out\"}"; echo "{\"field\": \"err\"}" 1>&2'`, core.DefaultReadParams())
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	rows := map[string]core.Row{}
+	for len(rows) < 3 {
+		select {
+		case row := <-rowsChan:
+			if row.Err != nil {
+				rows["exit"] = row
+			} else {
+				rows[row.Source] = row
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timeout")
+		}
+	}
+	assert.Equal(t, "out", rows["stdout"].Data["field"])
+	assert.Equal(t, "err", rows["stderr"].Data["field"])
+	assert.Contains(t, rows["exit"].Err.Error(), "restart in 1s")
+}
+
+func TestRowProvider_WatchCommand_Stop(t *testing.T) {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+
+	rowsChan, err := NewRowProvider().WatchCommand(ctx, "sleep 10", core.DefaultReadParams())
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	time.Sleep(10 * time.Millisecond)
+	cancelCtx()
+	select {
+	case _, ok := <-rowsChan:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("command is not stopped")
+	}
+}
+
+func TestRowProvider_WatchCommand_StopWhileSending(t *testing.T) {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+
+	rowsChan, err := NewRowProvider().WatchCommand(ctx, `yes '{"field": "value"}'`, core.DefaultReadParams())
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	// readers are blocked on sending to full channel
+	time.Sleep(20 * time.Millisecond)
+	cancelCtx()
+	// readers must not wait for consumer after cancel
+	time.Sleep(20 * time.Millisecond)
+	received := 0
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-rowsChan:
+			if !ok {
+				assert.True(t, received <= cap(rowsChan)+2, "received %d rows after cancel", received)
+				return
+			}
+			received++
+		case <-timeout:
+			t.Fatal("command is not stopped")
+		}
+	}
+}
+
+func TestSplitCommandLine(t *testing.T) {
+	cases := []struct {
+		in       string
+		expected []string
+		err      bool
+	}{
+		{"kubectl logs -f pod", []string{"kubectl", "logs", "-f", "pod"}, false},
+		{"  cmd   arg  ", []string{"cmd", "arg"}, false},
+		{`cmd "some arg" 'other "arg"'`, []string{"cmd", "some arg", `other "arg"`}, false},
+		{`cmd some\ arg ""`, []string{"cmd", "some arg", ""}, false},
+		{"", []string{}, false},
+		{`cmd "arg`, nil, true},
+		{`cmd arg\`, nil, true},
+	}
+	for i, cs := range cases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			actual, err := splitCommandLine(cs.in)
+			assert.Equal(t, cs.expected, actual)
+			assert.Equal(t, cs.err, err != nil)
+		})
+	}
+}
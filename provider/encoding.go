@@ -0,0 +1,187 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+const (
+	encodingAuto    = "auto"
+	encodingUtf8    = "utf-8"
+	encodingUtf16le = "utf-16le"
+	encodingUtf16be = "utf-16be"
+)
+
+var (
+	bomUtf8    = []byte{0xef, 0xbb, 0xbf}
+	bomUtf16le = []byte{0xff, 0xfe}
+	bomUtf16be = []byte{0xfe, 0xff}
+)
+
+// normalizeEncoding checks encoding name and converts it to one of known names
+func normalizeEncoding(encoding string) (string, error) {
+	switch strings.Replace(strings.ToLower(strings.TrimSpace(encoding)), "_", "-", -1) {
+	case "", encodingAuto:
+		return encodingAuto, nil
+	case encodingUtf8, "utf8":
+		return encodingUtf8, nil
+	case encodingUtf16le, "utf16le", "utf-16":
+		return encodingUtf16le, nil
+	case encodingUtf16be, "utf16be":
+		return encodingUtf16be, nil
+	default:
+		return "", errors.New("unknown encoding: " + encoding)
+	}
+}
+
+// detectEncoding returns encoding by byte order mark in the beginning of data and length of the mark
+func detectEncoding(head []byte) (string, int) {
+	switch {
+	case bytes.HasPrefix(head, bomUtf8):
+		return encodingUtf8, len(bomUtf8)
+	case bytes.HasPrefix(head, bomUtf16le):
+		return encodingUtf16le, len(bomUtf16le)
+	case bytes.HasPrefix(head, bomUtf16be):
+		return encodingUtf16be, len(bomUtf16be)
+	default:
+		return encodingUtf8, 0
+	}
+}
+
+// detectFileEncoding returns encoding of file, encoding 'auto' is detected by byte order mark in the beginning of file
+func detectFileEncoding(file io.ReaderAt, encoding string) (string, error) {
+	encoding, err := normalizeEncoding(encoding)
+	if err != nil {
+		return "", err
+	}
+	if encoding != encodingAuto {
+		return encoding, nil
+	}
+	head := make([]byte, len(bomUtf8))
+	n, _ := file.ReadAt(head, 0)
+	encoding, _ = detectEncoding(head[:n])
+	return encoding, nil
+}
+
+// newDecodingReader skips byte order mark and transcodes stream to UTF-8.
+// Encoding 'auto' is detected by byte order mark, UTF-8 is used if there is no mark.
+func newDecodingReader(r io.Reader, encoding string) (io.Reader, error) {
+	encoding, err := normalizeEncoding(encoding)
+	if err != nil {
+		return nil, err
+	}
+	reader := bufio.NewReaderSize(r, maxBytesInRow)
+	head, _ := reader.Peek(len(bomUtf8))
+	detected, bomLength := detectEncoding(head)
+	if encoding == encodingAuto || encoding == detected {
+		encoding = detected
+		reader.Discard(bomLength)
+	}
+	return newTranscoder(reader, encoding), nil
+}
+
+// newTranscoder returns reader which converts text from encoding to UTF-8, byte order mark is not expected
+func newTranscoder(r io.Reader, encoding string) io.Reader {
+	switch encoding {
+	case encodingUtf16le:
+		return &utf16Reader{rd: r, bigEndian: false}
+	case encodingUtf16be:
+		return &utf16Reader{rd: r, bigEndian: true}
+	default:
+		return r
+	}
+}
+
+// utf16Reader converts UTF-16 stream to UTF-8. It doesn't stop on EOF of underlying reader,
+// so it can be used for watching growing file.
+type utf16Reader struct {
+	rd        io.Reader
+	bigEndian bool
+	in        []byte
+	out       []byte
+	highHalf  rune
+}
+
+func (u *utf16Reader) Read(p []byte) (int, error) {
+	for len(u.out) == 0 {
+		buf := make([]byte, len(p)+1)
+		n, err := u.rd.Read(buf)
+		u.in = append(u.in, buf[:n]...)
+		u.decode()
+		if err != nil && len(u.out) == 0 {
+			return 0, err
+		}
+		if n == 0 && err == nil && len(u.out) == 0 {
+			return 0, nil
+		}
+	}
+	n := copy(p, u.out)
+	u.out = u.out[n:]
+	return n, nil
+}
+
+func (u *utf16Reader) decode() {
+	var runeBuf [utf8.UTFMax]byte
+	i := 0
+	for ; i+1 < len(u.in); i += 2 {
+		var unit rune
+		if u.bigEndian {
+			unit = rune(u.in[i])<<8 | rune(u.in[i+1])
+		} else {
+			unit = rune(u.in[i+1])<<8 | rune(u.in[i])
+		}
+		var r rune
+		switch {
+		case u.highHalf != 0:
+			r = utf16.DecodeRune(u.highHalf, unit)
+			u.highHalf = 0
+			if r == utf8.RuneError {
+				// unpaired high surrogate, current unit is decoded separately
+				n := utf8.EncodeRune(runeBuf[:], utf8.RuneError)
+				u.out = append(u.out, runeBuf[:n]...)
+				i -= 2
+				continue
+			}
+		case utf16.IsSurrogate(unit) && unit < 0xdc00:
+			u.highHalf = unit
+			continue
+		case utf16.IsSurrogate(unit):
+			r = utf8.RuneError
+		default:
+			r = unit
+		}
+		n := utf8.EncodeRune(runeBuf[:], r)
+		u.out = append(u.out, runeBuf[:n]...)
+	}
+	u.in = u.in[:copy(u.in, u.in[i:])]
+}
+
+// repairUtf8 replaces every invalid UTF-8 sequence in line with U+FFFD and returns count of replacements
+func repairUtf8(line []byte) ([]byte, int) {
+	if utf8.Valid(line) {
+		return line, 0
+	}
+	repaired := make([]byte, 0, len(line)+8)
+	count := 0
+	invalid := false
+	for len(line) > 0 {
+		r, size := utf8.DecodeRune(line)
+		if r == utf8.RuneError && size == 1 {
+			if !invalid {
+				repaired = append(repaired, "\uFFFD"...)
+				count++
+				invalid = true
+			}
+		} else {
+			repaired = append(repaired, line[:size]...)
+			invalid = false
+		}
+		line = line[size:]
+	}
+	return repaired, count
+}
@@ -0,0 +1,174 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/voronelf/logview/core"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+	"unicode/utf16"
+)
+
+func encodeUtf16(text string, bigEndian bool) []byte {
+	result := []byte{}
+	for _, unit := range utf16.Encode([]rune(text)) {
+		if bigEndian {
+			result = append(result, byte(unit>>8), byte(unit))
+		} else {
+			result = append(result, byte(unit), byte(unit>>8))
+		}
+	}
+	return result
+}
+
+func TestNewDecodingReader(t *testing.T) {
+	text := "{\"field\": \"Привет 😀\"}\n"
+	cases := []struct {
+		input    []byte
+		encoding string
+	}{
+		{[]byte(text), "auto"},
+		{append(append([]byte{}, bomUtf8...), text...), "auto"},
+		{append(append([]byte{}, bomUtf8...), text...), "utf-8"},
+		{append(append([]byte{}, bomUtf16le...), encodeUtf16(text, false)...), "auto"},
+		{append(append([]byte{}, bomUtf16be...), encodeUtf16(text, true)...), "auto"},
+		{encodeUtf16(text, false), "utf-16le"},
+		{encodeUtf16(text, true), "UTF-16BE"},
+		{append(append([]byte{}, bomUtf16be...), encodeUtf16(text, true)...), "utf-16be"},
+	}
+	for i, cs := range cases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			reader, err := newDecodingReader(bytes.NewReader(cs.input), cs.encoding)
+			if !assert.Nil(t, err) {
+				t.FailNow()
+			}
+			actual, err := ioutil.ReadAll(reader)
+			assert.Nil(t, err)
+			assert.Equal(t, text, string(actual))
+		})
+	}
+}
+
+func TestNewDecodingReader_UnknownEncoding(t *testing.T) {
+	_, err := newDecodingReader(bytes.NewReader([]byte{}), "koi8-r")
+	assert.NotNil(t, err)
+}
+
+func TestUtf16Reader_InvalidSurrogates(t *testing.T) {
+	input := []byte{0x3d, 0xd8, 'a', 0x00, 0x00, 0xdc, 'b', 0x00}
+	actual, err := ioutil.ReadAll(newTranscoder(bytes.NewReader(input), encodingUtf16le))
+	assert.Nil(t, err)
+	assert.Equal(t, "�a�b", string(actual))
+}
+
+func TestRepairUtf8(t *testing.T) {
+	cases := []struct {
+		in       string
+		expected string
+		count    int
+	}{
+		{"valid строка", "valid строка", 0},
+		{"a\xffb", "a�b", 1},
+		{"a\xff\xfeb\xc3", "a�b�", 2},
+	}
+	for i, cs := range cases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			actual, count := repairUtf8([]byte(cs.in))
+			assert.Equal(t, cs.expected, string(actual))
+			assert.Equal(t, cs.count, count)
+		})
+	}
+}
+
+func TestCreateRow_InvalidUtf8(t *testing.T) {
+	row := createRow([]byte("{\"field\": \"a\xffb\"}"))
+	assert.Nil(t, row.Err)
+	assert.Equal(t, "a�b", row.Data["field"])
+	assert.Equal(t, 1, row.Repaired)
+}
+
+func TestRowProvider_ReadFileTail_Utf16(t *testing.T) {
+	fd, err := ioutil.TempFile("", "go_test_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { fd.Close(); os.Remove(fd.Name()) }()
+	fd.Write(bomUtf16le)
+	fd.Write(encodeUtf16("{\"field\": \"1\"}\n{\"field\": \"2\"}\n", false))
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+
+	for _, countBytes := range []int64{0, 31} {
+		rowsChan, err := NewRowProvider().ReadFileTail(ctx, fd.Name(), countBytes, core.DefaultReadParams())
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		rows := []core.Row{}
+		for row := range rowsChan {
+			rows = append(rows, row)
+		}
+		if assert.NotEmpty(t, rows) {
+			last := rows[len(rows)-1]
+			assert.Nil(t, last.Err)
+			assert.Equal(t, "2", last.Data["field"])
+		}
+	}
+}
+
+func TestRowProvider_ReadFileTail_BytesGreaterThanFile(t *testing.T) {
+	text := "{\"field\": \"1\"}\n{\"field\": \"2\"}\n"
+	cases := [][]byte{
+		append(append([]byte{}, bomUtf8...), text...),
+		append(append([]byte{}, bomUtf16le...), encodeUtf16(text, false)...),
+	}
+	for i, content := range cases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			fd, err := ioutil.TempFile("", "go_test_")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() { fd.Close(); os.Remove(fd.Name()) }()
+			fd.Write(content)
+
+			rowsChan, err := NewRowProvider().ReadFileTail(context.Background(), fd.Name(), 1000, core.DefaultReadParams())
+			if !assert.Nil(t, err) {
+				t.FailNow()
+			}
+			values := []interface{}{}
+			for row := range rowsChan {
+				assert.Nil(t, row.Err)
+				values = append(values, row.Data["field"])
+			}
+			assert.Equal(t, []interface{}{"1", "2"}, values)
+		})
+	}
+}
+
+func TestRowProvider_WatchFileChanges_Utf16(t *testing.T) {
+	fd, err := ioutil.TempFile("", "go_test_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { fd.Close(); os.Remove(fd.Name()) }()
+	fd.Write(bomUtf16be)
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+
+	rowsChan, err := NewRowProvider().WatchFileChanges(ctx, fd.Name(), core.DefaultReadParams())
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	time.Sleep(time.Millisecond)
+	fd.Write(encodeUtf16("{\"field\": \"ü\"}\n", true))
+	select {
+	case row := <-rowsChan:
+		assert.Nil(t, row.Err)
+		assert.Equal(t, "ü", row.Data["field"])
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+}
@@ -0,0 +1,287 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/voronelf/logview/core"
+	"io"
+	"io/ioutil"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	gelfMaxChunks     = 128
+	gelfChunkTimeout  = 5 * time.Second
+	gelfMaxPacketSize = 65536
+	gelfChunkHeader   = 12
+	// gelfMaxMessageSize limits size of decompressed message
+	gelfMaxMessageSize = 8 << 20
+	// gelfMaxAcceptDelay limits delay between retries of failed accepting of TCP connection
+	gelfMaxAcceptDelay = time.Second
+)
+
+var gelfLevels = []string{"emergency", "alert", "critical", "error", "warning", "notice", "info", "debug"}
+
+func (r *rowProvider) ListenGelf(ctx context.Context, network, address string) (<-chan core.Row, error) {
+	outputCh := make(chan core.Row, 16)
+	switch network {
+	case "udp":
+		conn, err := net.ListenPacket(network, address)
+		if err != nil {
+			return nil, err
+		}
+		go func() {
+			<-ctx.Done()
+			conn.Close()
+		}()
+		go r.readGelfPackets(ctx, conn, outputCh)
+	case "tcp":
+		listener, err := net.Listen(network, address)
+		if err != nil {
+			return nil, err
+		}
+		go func() {
+			<-ctx.Done()
+			listener.Close()
+		}()
+		go r.acceptGelfConnections(ctx, listener, outputCh)
+	default:
+		return nil, errors.New("unknown network for GELF: " + network)
+	}
+	return outputCh, nil
+}
+
+func (r *rowProvider) readGelfPackets(ctx context.Context, conn net.PacketConn, outputCh chan<- core.Row) {
+	defer close(outputCh)
+	chunks := newGelfChunks()
+	buf := make([]byte, gelfMaxPacketSize)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			sendRow(ctx, core.Row{Data: map[string]interface{}{}, Err: err}, outputCh)
+			continue
+		}
+		packet := buf[:n]
+		if isGelfChunk(packet) {
+			packet, err = chunks.add(packet, time.Now())
+			if err != nil {
+				sendRow(ctx, core.Row{Data: map[string]interface{}{}, Err: err}, outputCh)
+				continue
+			}
+			if packet == nil {
+				continue
+			}
+		} else {
+			packet = append([]byte{}, packet...)
+		}
+		sendRow(ctx, createGelfRow(packet), outputCh)
+	}
+}
+
+// acceptGelfConnections reads messages from every connection, outputCh is closed after all connections are finished.
+// Accepting is retried with growing delay on temporary errors, like in net/http, and is stopped on other errors.
+func (r *rowProvider) acceptGelfConnections(ctx context.Context, listener net.Listener, outputCh chan<- core.Row) {
+	wg := &sync.WaitGroup{}
+	defer close(outputCh)
+	defer wg.Wait()
+	var delay time.Duration
+	for {
+		conn, err := listener.Accept()
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			if !sendRow(ctx, core.Row{Data: map[string]interface{}{}, Err: err}, outputCh) {
+				return
+			}
+			if netErr, ok := err.(net.Error); !ok || !netErr.Temporary() {
+				return
+			}
+			delay = nextAcceptDelay(delay)
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return
+			}
+			continue
+		}
+		delay = 0
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			doneCh := make(chan struct{})
+			defer close(doneCh)
+			defer conn.Close()
+			go func() {
+				select {
+				case <-ctx.Done():
+					conn.Close()
+				case <-doneCh:
+				}
+			}()
+			reader := bufio.NewReader(conn)
+			for ctx.Err() == nil {
+				message, err := reader.ReadBytes(0)
+				if len(message) > 0 && message[len(message)-1] == 0 {
+					message = message[:len(message)-1]
+				}
+				if len(bytes.TrimSpace(message)) > 0 && !sendRow(ctx, createGelfRow(message), outputCh) {
+					return
+				}
+				if err != nil {
+					return
+				}
+			}
+		}()
+	}
+}
+
+// nextAcceptDelay doubles delay of accepting from 5ms up to gelfMaxAcceptDelay
+func nextAcceptDelay(delay time.Duration) time.Duration {
+	if delay == 0 {
+		return 5 * time.Millisecond
+	}
+	delay *= 2
+	if delay > gelfMaxAcceptDelay {
+		return gelfMaxAcceptDelay
+	}
+	return delay
+}
+
+func isGelfChunk(packet []byte) bool {
+	return len(packet) > 1 && packet[0] == 0x1e && packet[1] == 0x0f
+}
+
+func newGelfChunks() *gelfChunks {
+	return &gelfChunks{messages: map[string]*gelfChunkedMessage{}}
+}
+
+// gelfChunks collects chunks of UDP messages until all of them are received
+type gelfChunks struct {
+	messages map[string]*gelfChunkedMessage
+}
+
+type gelfChunkedMessage struct {
+	parts    [][]byte
+	received int
+	started  time.Time
+}
+
+// add saves chunk and returns whole message if all its chunks are received
+func (g *gelfChunks) add(packet []byte, now time.Time) ([]byte, error) {
+	for id, message := range g.messages {
+		if now.Sub(message.started) > gelfChunkTimeout {
+			delete(g.messages, id)
+		}
+	}
+	if len(packet) < gelfChunkHeader {
+		return nil, errors.New("GELF chunk is too short")
+	}
+	id := string(packet[2:10])
+	seqNum, seqCount := int(packet[10]), int(packet[11])
+	if seqCount == 0 || seqCount > gelfMaxChunks || seqNum >= seqCount {
+		return nil, errors.New("wrong GELF chunk sequence " + strconv.Itoa(seqNum) + "/" + strconv.Itoa(seqCount))
+	}
+	message, ok := g.messages[id]
+	if !ok {
+		message = &gelfChunkedMessage{parts: make([][]byte, seqCount), started: now}
+		g.messages[id] = message
+	}
+	if len(message.parts) != seqCount {
+		delete(g.messages, id)
+		return nil, errors.New("GELF chunks of one message have different sequence count")
+	}
+	if message.parts[seqNum] == nil {
+		message.parts[seqNum] = append([]byte{}, packet[gelfChunkHeader:]...)
+		message.received++
+	}
+	if message.received < seqCount {
+		return nil, nil
+	}
+	delete(g.messages, id)
+	return bytes.Join(message.parts, nil), nil
+}
+
+// decompressGelf unpacks gzip and zlib payloads, other payloads are returned as is.
+// Unpacked message must not be greater than gelfMaxMessageSize.
+func decompressGelf(payload []byte) ([]byte, error) {
+	var reader io.ReadCloser
+	var err error
+	switch {
+	case len(payload) > 1 && payload[0] == 0x1f && payload[1] == 0x8b:
+		reader, err = gzip.NewReader(bytes.NewReader(payload))
+	case len(payload) > 1 && payload[0]&0x0f == 0x08 && (int(payload[0])<<8|int(payload[1]))%31 == 0:
+		reader, err = zlib.NewReader(bytes.NewReader(payload))
+	default:
+		return payload, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	message, err := ioutil.ReadAll(io.LimitReader(reader, gelfMaxMessageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(message) > gelfMaxMessageSize {
+		return nil, errors.New("GELF message is greater than " + strconv.Itoa(gelfMaxMessageSize) + " bytes")
+	}
+	return message, nil
+}
+
+func createGelfRow(payload []byte) core.Row {
+	row := core.Row{Data: map[string]interface{}{}}
+	payload, row.Err = decompressGelf(payload)
+	if row.Err != nil {
+		return row
+	}
+	message := map[string]interface{}{}
+	fields, err := decodeObject(payload, message)
+	if err != nil {
+		row.Err = err
+		return row
+	}
+	for _, key := range fields {
+		value := message[key]
+		switch {
+		case key == "version":
+			continue
+		case key == "short_message":
+			key = "message"
+		case key == "level":
+			value = gelfLevelName(value)
+		case strings.HasPrefix(key, "_") && len(key) > 1:
+			key = key[1:]
+		}
+		if _, exists := row.Data[key]; !exists {
+			row.Fields = append(row.Fields, key)
+		}
+		row.Data[key] = value
+	}
+	return row
+}
+
+// gelfLevelName converts syslog severity number to level name
+func gelfLevelName(value interface{}) interface{} {
+	number, ok := value.(json.Number)
+	if !ok {
+		return value
+	}
+	level, err := number.Int64()
+	if err != nil || level < 0 || level >= int64(len(gelfLevels)) {
+		return value
+	}
+	return gelfLevels[level]
+}
@@ -0,0 +1,246 @@
+package provider
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/voronelf/logview/core"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+const gelfMessage = `{"version":"1.1","host":"example.org","short_message":"Short","full_message":"Full text","level":3,"_user_id":9001}`
+
+func gzipBytes(data []byte) []byte {
+	buf := &bytes.Buffer{}
+	w := gzip.NewWriter(buf)
+	w.Write(data)
+	w.Close()
+	return buf.Bytes()
+}
+
+func zlibBytes(data []byte) []byte {
+	buf := &bytes.Buffer{}
+	w := zlib.NewWriter(buf)
+	w.Write(data)
+	w.Close()
+	return buf.Bytes()
+}
+
+func gelfChunk(id string, seqNum, seqCount byte, data []byte) []byte {
+	return append(append([]byte{0x1e, 0x0f}, append([]byte(id), seqNum, seqCount)...), data...)
+}
+
+func TestCreateGelfRow(t *testing.T) {
+	expected := map[string]interface{}{
+		"host":         "example.org",
+		"message":      "Short",
+		"full_message": "Full text",
+		"level":        "error",
+		"user_id":      json.Number("9001"),
+	}
+	for name, payload := range map[string][]byte{
+		"plain": []byte(gelfMessage),
+		"gzip":  gzipBytes([]byte(gelfMessage)),
+		"zlib":  zlibBytes([]byte(gelfMessage)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			row := createGelfRow(payload)
+			assert.Nil(t, row.Err)
+			assert.Equal(t, expected, row.Data)
+			assert.Equal(t, []string{"host", "message", "full_message", "level", "user_id"}, row.Fields)
+		})
+	}
+}
+
+func TestCreateGelfRow_Err(t *testing.T) {
+	row := createGelfRow([]byte("not json"))
+	assert.NotNil(t, row.Err)
+	row = createGelfRow([]byte{0x1f, 0x8b, 0x00})
+	assert.NotNil(t, row.Err)
+}
+
+func TestCreateGelfRow_TooBig(t *testing.T) {
+	row := createGelfRow(gzipBytes(bytes.Repeat([]byte(" "), gelfMaxMessageSize+1)))
+	assert.EqualError(t, row.Err, "GELF message is greater than 8388608 bytes")
+	row = createGelfRow(gzipBytes(append([]byte(gelfMessage), bytes.Repeat([]byte(" "), gelfMaxMessageSize-len(gelfMessage))...)))
+	assert.Nil(t, row.Err)
+}
+
+func TestGelfChunks_add(t *testing.T) {
+	chunks := newGelfChunks()
+	now := time.Now()
+	payload := gzipBytes([]byte(gelfMessage))
+	half := len(payload) / 2
+
+	message, err := chunks.add(gelfChunk("message1", 1, 2, payload[half:]), now)
+	assert.Nil(t, err)
+	assert.Nil(t, message)
+	message, err = chunks.add(gelfChunk("message2", 0, 2, []byte("lost")), now)
+	assert.Nil(t, err)
+	assert.Nil(t, message)
+	message, err = chunks.add(gelfChunk("message1", 0, 2, payload[:half]), now)
+	assert.Nil(t, err)
+	assert.Equal(t, payload, message)
+
+	_, err = chunks.add(gelfChunk("message3", 0, 0, []byte{}), now)
+	assert.NotNil(t, err)
+	_, err = chunks.add(gelfChunk("message3", 0, 129, []byte{}), now)
+	assert.NotNil(t, err)
+
+	chunks.add(gelfChunk("message4", 0, 2, []byte{}), now.Add(2*gelfChunkTimeout))
+	assert.Len(t, chunks.messages, 1)
+}
+
+func TestRowProvider_ListenGelf_Udp(t *testing.T) {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+	address := freeAddress(t, "udp")
+	rowsChan, err := NewRowProvider().ListenGelf(ctx, "udp", address)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	conn, err := net.Dial("udp", address)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	defer conn.Close()
+	payload := zlibBytes([]byte(gelfMessage))
+	conn.Write(gelfChunk("message1", 0, 2, payload[:5]))
+	conn.Write(gelfChunk("message1", 1, 2, payload[5:]))
+
+	row := receiveRow(t, rowsChan)
+	assert.Nil(t, row.Err)
+	assert.Equal(t, "Short", row.Data["message"])
+}
+
+func TestRowProvider_ListenGelf_Tcp(t *testing.T) {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+	address := freeAddress(t, "tcp")
+	rowsChan, err := NewRowProvider().ListenGelf(ctx, "tcp", address)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	conn, err := net.Dial("tcp", address)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	defer conn.Close()
+	conn.Write([]byte(gelfMessage + "\x00" + `{"short_message":"Second"}` + "\x00"))
+
+	row := receiveRow(t, rowsChan)
+	assert.Equal(t, "Short", row.Data["message"])
+	row = receiveRow(t, rowsChan)
+	assert.Equal(t, "Second", row.Data["message"])
+}
+
+func TestRowProvider_ListenGelf_TcpStop(t *testing.T) {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	address := freeAddress(t, "tcp")
+	rowsChan, err := NewRowProvider().ListenGelf(ctx, "tcp", address)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	conn, err := net.Dial("tcp", address)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	defer conn.Close()
+	// connection is blocked on sending to full channel
+	conn.Write([]byte(strings.Repeat(gelfMessage+"\x00", 2*cap(rowsChan))))
+	time.Sleep(20 * time.Millisecond)
+	cancelCtx()
+	time.Sleep(20 * time.Millisecond)
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-rowsChan:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("listener is not stopped")
+		}
+	}
+}
+
+// failingListener returns errors from Accept one by one
+type failingListener struct {
+	net.Listener
+	errs []error
+}
+
+func (l *failingListener) Accept() (net.Conn, error) {
+	err := l.errs[0]
+	l.errs = l.errs[1:]
+	return nil, err
+}
+
+type temporaryError struct{}
+
+func (temporaryError) Error() string   { return "too many open files" }
+func (temporaryError) Timeout() bool   { return false }
+func (temporaryError) Temporary() bool { return true }
+
+func TestRowProvider_acceptGelfConnections_Err(t *testing.T) {
+	listener := &failingListener{errs: []error{temporaryError{}, temporaryError{}, errors.New("closed")}}
+	rowsChan := make(chan core.Row, 16)
+	started := time.Now()
+	NewRowProvider().acceptGelfConnections(context.Background(), listener, rowsChan)
+
+	// accepting is retried after 5ms and 10ms and is stopped on not temporary error
+	assert.True(t, time.Since(started) >= 15*time.Millisecond)
+	messages := []string{}
+	for row := range rowsChan {
+		messages = append(messages, row.Err.Error())
+	}
+	assert.Equal(t, []string{"too many open files", "too many open files", "closed"}, messages)
+}
+
+func TestNextAcceptDelay(t *testing.T) {
+	assert.Equal(t, 5*time.Millisecond, nextAcceptDelay(0))
+	assert.Equal(t, 10*time.Millisecond, nextAcceptDelay(5*time.Millisecond))
+	assert.Equal(t, gelfMaxAcceptDelay, nextAcceptDelay(800*time.Millisecond))
+}
+
+func TestRowProvider_ListenGelf_Err(t *testing.T) {
+	_, err := NewRowProvider().ListenGelf(context.Background(), "unix", "/tmp/some")
+	assert.NotNil(t, err)
+}
+
+func freeAddress(t *testing.T, network string) string {
+	var address string
+	if network == "udp" {
+		conn, err := net.ListenPacket(network, "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		address = conn.LocalAddr().String()
+		conn.Close()
+	} else {
+		listener, err := net.Listen(network, "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		address = listener.Addr().String()
+		listener.Close()
+	}
+	return address
+}
+
+func receiveRow(t *testing.T, rowsChan <-chan core.Row) core.Row {
+	select {
+	case row := <-rowsChan:
+		return row
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+	return core.Row{}
+}
@@ -2,6 +2,7 @@ package provider
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -20,14 +21,20 @@ type rowProvider struct {
 
 var _ core.RowProvider = (*rowProvider)(nil)
 
-func (r *rowProvider) WatchFileChanges(ctx context.Context, filePath string) (<-chan core.Row, error) {
+func (r *rowProvider) WatchFileChanges(ctx context.Context, filePath string, params core.ReadParams) (<-chan core.Row, error) {
 	outputCh := make(chan core.Row, 16)
 	file, err := os.Open(filePath)
 	if err != nil {
 		return outputCh, err
 	}
+	encoding, err := detectFileEncoding(file, params.Encoding)
+	if err != nil {
+		file.Close()
+		return outputCh, err
+	}
 	_, err = file.Seek(0, io.SeekEnd)
 	if err != nil {
+		file.Close()
 		return outputCh, err
 	}
 	watcher, err := fsnotify.NewWatcher()
@@ -44,7 +51,7 @@ func (r *rowProvider) WatchFileChanges(ctx context.Context, filePath string) (<-
 			watcher.Close()
 			file.Close()
 		}()
-		reader := newReaderIgnoreEOF(file, outputCh)
+		reader := newReaderIgnoreEOF(newTranscoder(file, encoding), outputCh)
 		for {
 			select {
 			case event := <-watcher.Events:
@@ -63,29 +70,49 @@ func (r *rowProvider) WatchFileChanges(ctx context.Context, filePath string) (<-
 	return outputCh, nil
 }
 
-func (r *rowProvider) WatchOpenedStream(ctx context.Context, stream io.Reader) (<-chan core.Row, error) {
+func (r *rowProvider) WatchOpenedStream(ctx context.Context, stream io.Reader, params core.ReadParams) (<-chan core.Row, error) {
+	encoding, err := normalizeEncoding(params.Encoding)
+	if err != nil {
+		return nil, err
+	}
 	filteredRowsCh := make(chan core.Row, 1)
 	go func() {
-		reader := bufio.NewReaderSize(stream, maxBytesInRow)
+		decodedStream, _ := newDecodingReader(stream, encoding)
+		reader := bufio.NewReaderSize(decodedStream, maxBytesInRow)
 		readUntilEOF(ctx, reader, filteredRowsCh)
 		close(filteredRowsCh)
 	}()
 	return filteredRowsCh, nil
 }
 
-func (r *rowProvider) ReadFileTail(ctx context.Context, filePath string, countBytes int64) (<-chan core.Row, error) {
+func (r *rowProvider) ReadFileTail(ctx context.Context, filePath string, countBytes int64, params core.ReadParams) (<-chan core.Row, error) {
 	outputCh := make(chan core.Row, 16)
 	fd, err := os.Open(filePath)
 	if err != nil {
 		return nil, err
 	}
-	if countBytes > 0 {
-		fd.Seek(-countBytes, io.SeekEnd)
+	encoding, err := detectFileEncoding(fd, params.Encoding)
+	if err != nil {
+		fd.Close()
+		return nil, err
+	}
+	offset, err := tailOffset(fd, countBytes, encoding)
+	if err != nil {
+		fd.Close()
+		return nil, err
+	}
+	var decodedFile io.Reader
+	if offset > 0 {
+		decodedFile = newTranscoder(fd, encoding)
+	} else {
+		// whole file is read, so byte order mark is skipped
+		decodedFile, _ = newDecodingReader(fd, encoding)
 	}
 	go func() {
 		defer fd.Close()
-		reader := bufio.NewReaderSize(fd, maxBytesInRow)
-		if countBytes > 0 {
+		reader := bufio.NewReaderSize(decodedFile, maxBytesInRow)
+		if offset > 0 {
+			// first row is cut by offset
 			reader.ReadLine()
 		}
 		readUntilEOF(ctx, reader, outputCh)
@@ -94,6 +121,27 @@ func (r *rowProvider) ReadFileTail(ctx context.Context, filePath string, countBy
 	return outputCh, nil
 }
 
+// tailOffset moves position of file to last countBytes bytes and returns it,
+// whole file is read, if countBytes is zero or greater than size of file
+func tailOffset(fd *os.File, countBytes int64, encoding string) (int64, error) {
+	if countBytes <= 0 {
+		return 0, nil
+	}
+	if encoding != encodingUtf8 && countBytes%2 != 0 {
+		// keep offset aligned to UTF-16 code units
+		countBytes++
+	}
+	stat, err := fd.Stat()
+	if err != nil {
+		return 0, err
+	}
+	offset := stat.Size() - countBytes
+	if offset < 0 {
+		offset = 0
+	}
+	return fd.Seek(offset, io.SeekStart)
+}
+
 func (r *rowProvider) sendRowWithErr(err error, outputCh chan<- core.Row) {
 	row := core.Row{
 		Data: map[string]interface{}{},
@@ -102,6 +150,16 @@ func (r *rowProvider) sendRowWithErr(err error, outputCh chan<- core.Row) {
 	outputCh <- row
 }
 
+// sendRow sends row unless context is done, false is returned if row is not sent
+func sendRow(ctx context.Context, row core.Row, outputCh chan<- core.Row) bool {
+	select {
+	case outputCh <- row:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
 const maxBytesInRow = 16384
 
 func readUntilEOF(ctx context.Context, reader *bufio.Reader, outputCh chan<- core.Row) {
@@ -138,10 +196,50 @@ func createRow(line []byte) core.Row {
 	row := core.Row{
 		Data: make(map[string]interface{}, 8),
 	}
-	row.Err = json.Unmarshal(line, &row.Data)
+	line, row.Repaired = repairUtf8(line)
+	row.Fields, row.Err = decodeObject(line, row.Data)
 	return row
 }
 
+// decodeObject decodes JSON object into data and returns its keys in original order.
+// Numbers are decoded as json.Number for keeping precision of big integers.
+func decodeObject(line []byte, data map[string]interface{}) ([]string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return nil, errors.New("row is not a JSON object")
+	}
+	fields := make([]string, 0, 8)
+	for decoder.More() {
+		token, err = decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		key := token.(string)
+		var value interface{}
+		err = decoder.Decode(&value)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := data[key]; !exists {
+			fields = append(fields, key)
+		}
+		data[key] = value
+	}
+	_, err = decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	if _, err = decoder.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after JSON object")
+	}
+	return fields, nil
+}
+
 func newReaderIgnoreEOF(r io.Reader, outputCh chan<- core.Row) *readerIgnoreEOF {
 	return &readerIgnoreEOF{
 		rd:       bufio.NewReaderSize(r, maxBytesInRow),
@@ -2,6 +2,7 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
 	"github.com/stretchr/testify/assert"
 	"github.com/voronelf/logview/core"
 	"io"
@@ -28,7 +29,7 @@ func startWatchFileChanges(t *testing.T) (io.Writer, *sync.Map, func()) {
 	tempFile, delTempFile := createFileWithJson(t)
 	ctx, cancelCtx := context.WithCancel(context.Background())
 
-	rowsChan, err := NewRowProvider().WatchFileChanges(ctx, tempFile.Name())
+	rowsChan, err := NewRowProvider().WatchFileChanges(ctx, tempFile.Name(), core.DefaultReadParams())
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
@@ -75,12 +76,12 @@ func TestRowProvider_WatchFileChanges_twoRows(t *testing.T) {
 	time.Sleep(2 * time.Millisecond)
 	value, loaded := results.Load(0)
 	if assert.True(t, loaded) {
-		assert.Equal(t, float64(777), value.(core.Row).Data["field"])
+		assert.Equal(t, json.Number("777"), value.(core.Row).Data["field"])
 		assert.Nil(t, value.(core.Row).Err)
 	}
 	value, loaded = results.Load(1)
 	if assert.True(t, loaded) {
-		assert.Equal(t, float64(888.99), value.(core.Row).Data["field"])
+		assert.Equal(t, json.Number("888.99"), value.(core.Row).Data["field"])
 		assert.Nil(t, value.(core.Row).Err)
 	}
 	_, loaded = results.Load(2)
@@ -125,7 +126,7 @@ func TestRowProvider_WatchFileChanges_emptyLine(t *testing.T) {
 func TestRowProvider_WatchFileChanges_Err(t *testing.T) {
 	ctx, cancelCtx := context.WithCancel(context.Background())
 	defer cancelCtx()
-	_, err := NewRowProvider().WatchFileChanges(ctx, "notExistsFilePath")
+	_, err := NewRowProvider().WatchFileChanges(ctx, "notExistsFilePath", core.DefaultReadParams())
 	assert.NotNil(t, err)
 }
 
@@ -137,7 +138,7 @@ func startReadFileTail(t *testing.T, countBytes int, bytesToAddInFile []byte) (*
 		tempFile.Write(bytesToAddInFile)
 	}
 
-	rowsChan, err := NewRowProvider().ReadFileTail(ctx, tempFile.Name(), int64(countBytes))
+	rowsChan, err := NewRowProvider().ReadFileTail(ctx, tempFile.Name(), int64(countBytes), core.DefaultReadParams())
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
@@ -213,7 +214,7 @@ func TestRowProvider_ReadFileTail_withoutDivider(t *testing.T) {
 func TestRowProvider_ReadFileTail_Err(t *testing.T) {
 	ctx, cancelCtx := context.WithCancel(context.Background())
 	defer cancelCtx()
-	_, err := NewRowProvider().ReadFileTail(ctx, "notExistsFilePath", 123)
+	_, err := NewRowProvider().ReadFileTail(ctx, "notExistsFilePath", 123, core.DefaultReadParams())
 	assert.NotNil(t, err)
 }
 
@@ -221,7 +222,7 @@ func startWatchOpenedStream(t *testing.T) (io.Writer, *sync.Map, func()) {
 	pipeReader, pipeWriter := io.Pipe()
 	ctx, cancelCtx := context.WithCancel(context.Background())
 
-	rowsChan, err := NewRowProvider().WatchOpenedStream(ctx, pipeReader)
+	rowsChan, err := NewRowProvider().WatchOpenedStream(ctx, pipeReader, core.DefaultReadParams())
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
@@ -268,12 +269,12 @@ func TestRowProvider_WatchOpenedStream_twoRows(t *testing.T) {
 	time.Sleep(time.Millisecond)
 	value, loaded := results.Load(0)
 	if assert.True(t, loaded) {
-		assert.Equal(t, float64(777), value.(core.Row).Data["field"])
+		assert.Equal(t, json.Number("777"), value.(core.Row).Data["field"])
 		assert.Nil(t, value.(core.Row).Err)
 	}
 	value, loaded = results.Load(1)
 	if assert.True(t, loaded) {
-		assert.Equal(t, float64(888.99), value.(core.Row).Data["field"])
+		assert.Equal(t, json.Number("888.99"), value.(core.Row).Data["field"])
 		assert.Nil(t, value.(core.Row).Err)
 	}
 	_, loaded = results.Load(2)
@@ -314,3 +315,18 @@ func TestRowProvider_WatchOpenedStream_emptyLine(t *testing.T) {
 	_, loaded := results.Load(0)
 	assert.False(t, loaded)
 }
+
+func TestCreateRow(t *testing.T) {
+	row := createRow([]byte(`{"time": "now", "id": 1234567890123456789, "level": "info", "nested": {"a": [1, 2]}}`))
+	assert.Nil(t, row.Err)
+	assert.Equal(t, []string{"time", "id", "level", "nested"}, row.Fields)
+	assert.Equal(t, json.Number("1234567890123456789"), row.Data["id"])
+	assert.Equal(t, map[string]interface{}{"a": []interface{}{json.Number("1"), json.Number("2")}}, row.Data["nested"])
+}
+
+func TestCreateRow_Err(t *testing.T) {
+	for _, line := range []string{`not json`, `[1, 2]`, `{"a": 1} {"b": 2}`, `{"a": 1`} {
+		row := createRow([]byte(line))
+		assert.NotNil(t, row.Err, line)
+	}
+}
@@ -26,12 +26,7 @@ type store struct {
 var _ core.Settings = (*store)(nil)
 
 func (s *store) GetTemplates() (map[string]core.Template, error) {
-	tree, err := toml.LoadFile(s.filePath)
-	if err != nil {
-		return nil, err
-	}
-	content := tomlContent{}
-	err = tree.Unmarshal(&content)
+	content, err := s.load()
 	if err != nil {
 		return nil, err
 	}
@@ -42,6 +37,48 @@ func (s *store) GetTemplates() (map[string]core.Template, error) {
 	return result, nil
 }
 
+// GetLists returns paths of files with lists by names of lists, there are no lists without settings file
+func (s *store) GetLists() (map[string]string, error) {
+	content, err := s.load()
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]string{}
+	for name, path := range content.Lists {
+		result[name] = path
+	}
+	return result, nil
+}
+
+// GetMacros returns conditions of macros by names, there are no macros without settings file
+func (s *store) GetMacros() (map[string]string, error) {
+	content, err := s.load()
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]string{}
+	for name, condition := range content.Macros {
+		result[name] = condition
+	}
+	return result, nil
+}
+
+func (s *store) load() (tomlContent, error) {
+	content := tomlContent{}
+	tree, err := toml.LoadFile(s.filePath)
+	if err != nil {
+		return content, err
+	}
+	err = tree.Unmarshal(&content)
+	return content, err
+}
+
 func (s *store) SaveTemplate(name string, tpl core.Template) error {
 	dir := filepath.Dir(s.filePath)
 	if _, e := os.Stat(dir); os.IsNotExist(e) {
@@ -82,4 +119,8 @@ func (s *store) SaveTemplate(name string, tpl core.Template) error {
 
 type tomlContent struct {
 	Templates map[string]map[string]string `toml:"templates"`
+	// Lists are paths of files with lists of values, they are used in conditions like '@name'
+	Lists map[string]string `toml:"lists"`
+	// Macros are named conditions, they are used in conditions like '@name'
+	Macros map[string]string `toml:"macros"`
 }
@@ -25,6 +25,32 @@ func TestStore_GetTemplates(t *testing.T) {
 	assert.Equal(t, map[string]core.Template{"tpl1": {"f": "fff", "c": "ccc"}}, actual)
 }
 
+func TestStore_GetLists(t *testing.T) {
+	s := NewStore()
+	s.filePath = "test/settings.toml"
+	actual, err := s.GetLists()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"office": "/etc/logview/office.txt"}, actual)
+
+	s.filePath = "test/not-exists.toml"
+	actual, err = s.GetLists()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{}, actual)
+}
+
+func TestStore_GetMacros(t *testing.T) {
+	s := NewStore()
+	s.filePath = "test/settings.toml"
+	actual, err := s.GetMacros()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"no_healthchecks": "not url: '/health*'"}, actual)
+
+	s.filePath = "test/not-exists.toml"
+	actual, err = s.GetMacros()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{}, actual)
+}
+
 func TestStore_SaveTemplate(t *testing.T) {
 	tempDir, err := ioutil.TempDir("", "logview_test_")
 	if err != nil {
@@ -0,0 +1,186 @@
+package severity
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// Level is normalized severity level, levels are ordered from Trace to Fatal
+type Level int
+
+const (
+	Unknown Level = iota
+	Trace
+	Debug
+	Info
+	Warn
+	Error
+	Fatal
+)
+
+// DefaultFields are names of level fields in popular loggers
+var DefaultFields = []string{"level", "lvl", "severity", "log.level", "loglevel", "levelname"}
+
+var levelNames = []string{"unknown", "trace", "debug", "info", "warn", "error", "fatal"}
+
+func (l Level) String() string {
+	if l < Unknown || int(l) >= len(levelNames) {
+		return levelNames[Unknown]
+	}
+	return levelNames[l]
+}
+
+// names are lower case names of levels used by different loggers
+var names = map[string]Level{
+	"trace": Trace, "trc": Trace, "finest": Trace, "finer": Trace, "verbose": Trace,
+	"debug": Debug, "dbg": Debug, "d": Debug, "fine": Debug,
+	"info": Info, "inf": Info, "i": Info, "information": Info, "informational": Info, "notice": Info,
+	"warn": Warn, "wrn": Warn, "w": Warn, "warning": Warn,
+	"error": Error, "err": Error, "e": Error, "eror": Error, "severe": Error,
+	"fatal": Fatal, "ftl": Fatal, "f": Fatal, "critical": Fatal, "crit": Fatal, "crt": Fatal,
+	"alert": Fatal, "emerg": Fatal, "emergency": Fatal, "panic": Fatal, "dpanic": Fatal,
+}
+
+// maxNameLen is length of longest name of level
+const maxNameLen = len("informational")
+
+// ParseName returns level by name like 'WARNING' or 'crit', case insensitive
+func ParseName(name string) Level {
+	if len(name) > maxNameLen {
+		return Unknown
+	}
+	var buf [maxNameLen]byte
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if 'A' <= c && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		buf[i] = c
+	}
+	return names[string(buf[:len(name)])]
+}
+
+// Parse returns level of field value. Value can be name of level or number: levels of bunyan
+// and pino from 10 (trace) to 60 (fatal) or syslog severity from 0 (emergency) to 7 (debug).
+func Parse(value interface{}) Level {
+	switch v := value.(type) {
+	case string:
+		if level := ParseName(v); level != Unknown {
+			return level
+		}
+		if number, err := strconv.ParseFloat(v, 64); err == nil {
+			return parseNumber(number)
+		}
+	case float64:
+		return parseNumber(v)
+	case int:
+		return parseNumber(float64(v))
+	case json.Number:
+		if number, err := v.Float64(); err == nil {
+			return parseNumber(number)
+		}
+	}
+	return Unknown
+}
+
+func parseNumber(number float64) Level {
+	switch {
+	case number >= 60:
+		return Fatal
+	case number >= 50:
+		return Error
+	case number >= 40:
+		return Warn
+	case number >= 30:
+		return Info
+	case number >= 20:
+		return Debug
+	case number >= 10:
+		return Trace
+	case number >= 8:
+		return Unknown
+	case number >= 7:
+		return Debug
+	case number >= 5:
+		return Info
+	case number >= 4:
+		return Warn
+	case number >= 3:
+		return Error
+	case number >= 0:
+		return Fatal
+	}
+	return Unknown
+}
+
+// NewNormalizer creates normalizer, which looks for level in fields in order of priority.
+// Default fields are used, if fields are empty.
+func NewNormalizer(fields []string) *Normalizer {
+	if len(fields) == 0 {
+		fields = DefaultFields
+	}
+	normalizer := &Normalizer{}
+	for _, field := range fields {
+		normalizer.fields = append(normalizer.fields, levelField{name: field, path: strings.Split(field, ".")})
+	}
+	return normalizer
+}
+
+// Normalizer finds level of row in one of level fields
+type Normalizer struct {
+	fields []levelField
+}
+
+type levelField struct {
+	name string
+	// path is used for nested objects, if row doesn't contain field with such name
+	path []string
+}
+
+// IsLevelField checks that name is one of level fields, case insensitive
+func (n *Normalizer) IsLevelField(name string) bool {
+	for _, field := range n.fields {
+		if strings.EqualFold(field.name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Find returns value and normalized level of first found level field, ok is false if there is no level field.
+// Level is Unknown, if value of field is not recognized.
+func (n *Normalizer) Find(data map[string]interface{}) (value interface{}, level Level, ok bool) {
+	for _, field := range n.fields {
+		if value, ok = field.lookup(data); ok && value != nil {
+			return value, Parse(value), true
+		}
+	}
+	return nil, Unknown, false
+}
+
+// lookup finds field by name, then case insensitive, then by path in nested objects
+func (f levelField) lookup(data map[string]interface{}) (interface{}, bool) {
+	if value, ok := data[f.name]; ok {
+		return value, true
+	}
+	for key, value := range data {
+		if strings.EqualFold(key, f.name) {
+			return value, true
+		}
+	}
+	if len(f.path) == 1 {
+		return nil, false
+	}
+	var value interface{} = data
+	for _, key := range f.path {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = object[key]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
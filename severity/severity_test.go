@@ -0,0 +1,100 @@
+package severity
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		value    interface{}
+		expected Level
+	}{
+		{"debug", Debug},
+		{"INFO", Info},
+		{"Warning", Warn},
+		{"WARN", Warn},
+		{"err", Error},
+		{"FATAL", Fatal},
+		{"CRITICAL", Fatal},
+		{"panic", Fatal},
+		{"trace", Trace},
+		{"notice", Info},
+		{"E", Error},
+		{"informational", Info},
+		{"something", Unknown},
+		{"", Unknown},
+		{"very long name of level", Unknown},
+		{float64(10), Trace},
+		{float64(20), Debug},
+		{float64(30), Info},
+		{float64(40), Warn},
+		{float64(50), Error},
+		{float64(60), Fatal},
+		{float64(35), Info},
+		{json.Number("50"), Error},
+		{"40", Warn},
+		{3, Error},
+		{float64(7), Debug},
+		{float64(0), Fatal},
+		{float64(9), Unknown},
+		{float64(-1), Unknown},
+		{true, Unknown},
+		{nil, Unknown},
+	}
+	for i, cs := range cases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			assert.Equal(t, cs.expected, Parse(cs.value))
+		})
+	}
+}
+
+func TestLevel_String(t *testing.T) {
+	assert.Equal(t, "warn", Warn.String())
+	assert.Equal(t, "fatal", Fatal.String())
+	assert.Equal(t, "unknown", Unknown.String())
+	assert.Equal(t, "unknown", Level(100).String())
+}
+
+func TestNormalizer_Find(t *testing.T) {
+	cases := []struct {
+		fields   []string
+		data     map[string]interface{}
+		value    interface{}
+		expected Level
+		ok       bool
+	}{
+		{nil, map[string]interface{}{"level": "error"}, "error", Error, true},
+		{nil, map[string]interface{}{"lvl": "WARN"}, "WARN", Warn, true},
+		{nil, map[string]interface{}{"level": float64(30)}, float64(30), Info, true},
+		{nil, map[string]interface{}{"severity": "CRITICAL"}, "CRITICAL", Fatal, true},
+		{nil, map[string]interface{}{"log": map[string]interface{}{"level": "debug"}}, "debug", Debug, true},
+		{nil, map[string]interface{}{"log.level": "info"}, "info", Info, true},
+		{nil, map[string]interface{}{"Level": "Error"}, "Error", Error, true},
+		{nil, map[string]interface{}{"level": "custom"}, "custom", Unknown, true},
+		{nil, map[string]interface{}{"level": nil, "lvl": "info"}, "info", Info, true},
+		{nil, map[string]interface{}{"message": "text"}, nil, Unknown, false},
+		{nil, map[string]interface{}{"log": "text"}, nil, Unknown, false},
+		{[]string{"sev"}, map[string]interface{}{"level": "info", "sev": "error"}, "error", Error, true},
+		{[]string{"sev"}, map[string]interface{}{"level": "info"}, nil, Unknown, false},
+	}
+	for i, cs := range cases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			value, level, ok := NewNormalizer(cs.fields).Find(cs.data)
+			assert.Equal(t, cs.value, value)
+			assert.Equal(t, cs.expected, level)
+			assert.Equal(t, cs.ok, ok)
+		})
+	}
+}
+
+func TestNormalizer_IsLevelField(t *testing.T) {
+	assert.True(t, NewNormalizer(nil).IsLevelField("level"))
+	assert.True(t, NewNormalizer(nil).IsLevelField("Severity"))
+	assert.True(t, NewNormalizer(nil).IsLevelField("log.level"))
+	assert.False(t, NewNormalizer(nil).IsLevelField("message"))
+	assert.True(t, NewNormalizer([]string{"sev"}).IsLevelField("sev"))
+	assert.False(t, NewNormalizer([]string{"sev"}).IsLevelField("level"))
+}